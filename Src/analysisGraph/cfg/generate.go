@@ -222,6 +222,12 @@ func (generator *cfgGenerator) linkBlock(fKey int) CFGBlock {
 	blockList := generator.funcBlockTable[fKey]
 
 	for i, block := range blockList {
+		// return 없이 panic 등의 호출로 끝나는 함수의 마지막 block 은 다음 block 이 없음
+		if i+1 == len(blockList) {
+			if branchBlock, ok := block.(*BranchBlock); !ok || branchBlock.BranchType() == FalseBranch || branchBlock.BranchType() == TrueBranch {
+				continue
+			}
+		}
 		if _, ok := block.(*CallBlock); ok {
 			// target := fmt.Sprint(callBlock._codeList[0].(*icg.ControlOpcode).Params().Front().Value)
 			// targetFK := generator.silcodeTable.StringPool().LookupSymbolNumber(target)
@@ -0,0 +1,124 @@
+package cfg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"WAH_prototype_go-master/Src/icg"
+	"WAH_prototype_go-master/Src/icg/symbolTable"
+)
+
+const lastBlockSrc = `package main
+
+func fail(msg string) {
+	panic(msg)
+}
+
+func check(x int) int {
+	if x < 0 {
+		panic("negative")
+	}
+	return x
+}
+
+func must(x int) int {
+	if x > 0 {
+		return x
+	}
+	panic("not positive")
+}
+
+func main() {
+	must(1)
+	check(1)
+	fail("unreachable")
+}
+`
+
+// genCFG ... : src 로 SIL 과 CFG 를 생성하여 함수 이름별 CFG 반환
+func genCFG(t *testing.T, src string) map[string]CFGBlock {
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, "main.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("main", fs, []*ast.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	strPoolGenerator := &symbolTable.StringPoolGenerator{}
+	strPoolGenerator.Init(info)
+	strPool, symTble, litTable := strPoolGenerator.GenFiles([]*ast.File{f})
+	silTable := icg.CodeGenFiles([]*ast.File{f}, fs, info, strPool, symTble, litTable)
+
+	graphs := make(map[string]CFGBlock)
+	for k, root := range Generate(silTable) {
+		graphs[strPool.LookupSymbolName(k)] = root
+	}
+	return graphs
+}
+
+// reachable ... : root 에서 도달할 수 있는 모든 block
+func reachable(root CFGBlock) []CFGBlock {
+	var res []CFGBlock
+	visited := make(map[CFGBlock]bool)
+	var visit func(block CFGBlock)
+	visit = func(block CFGBlock) {
+		if block == nil || visited[block] {
+			return
+		}
+		visited[block] = true
+		res = append(res, block)
+		switch b := block.(type) {
+		case *CallBlock:
+			visit(b.UjpBlock())
+		case *BranchBlock:
+			visit(b.TargetBlock())
+			visit(b.UjpBlock())
+		case *BasicBlock:
+			visit(b.LinkedBlock())
+		}
+	}
+	visit(root)
+	return res
+}
+
+// panicBlock ... : 함수의 CFG 에서 panic 을 호출하는 block
+func panicBlock(t *testing.T, root CFGBlock) *CallBlock {
+	for _, block := range reachable(root) {
+		if b, ok := block.(*CallBlock); ok && fmt.Sprint(b.CodeList()[0].(*icg.ControlOpcode).Params().Front().Value) == "panic" {
+			return b
+		}
+	}
+	t.Fatal("no block calls panic")
+	return nil
+}
+
+// 반환 값이 있는 함수가 return 없이 panic 호출로 끝나면 SIL 의 마지막 block 이 call block 이 됨
+func TestLinkBlockLastCall(t *testing.T) {
+	graphs := genCFG(t, lastBlockSrc)
+
+	if b := panicBlock(t, graphs["must"]); b.UjpBlock() != nil {
+		t.Errorf("must: the last call block is linked to %v, want no next block", b.UjpBlock())
+	}
+	if b := panicBlock(t, graphs["fail"]); b.UjpBlock() == nil {
+		t.Error("fail: the call block before ret is not linked")
+	} else if _, ok := b.UjpBlock().(*ReturnBlock); !ok {
+		t.Errorf("fail: the call block is linked to %T, want *cfg.ReturnBlock", b.UjpBlock())
+	}
+	if b := panicBlock(t, graphs["check"]); b.UjpBlock() == nil {
+		t.Error("check: the call block inside if is not linked")
+	}
+}
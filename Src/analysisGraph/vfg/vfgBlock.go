@@ -2,6 +2,7 @@ package vfg
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -29,7 +30,7 @@ func (c *DUChain) Print() {
 			fmt.Printf("\t  definition : %d Use :", set.Definition)
 			fmt.Println(set.Use)
 		}
-		fmt.Println("\t--------------------------------\n")
+		fmt.Print("\t--------------------------------\n\n")
 	}
 }
 func (c *DUChain) String() string {
@@ -58,6 +59,17 @@ func (c *DUChain) LookUpFull(offset string) []*DUSet {
 	return res
 }
 
+// Offsets ... : DU chain 이 있는 offset 목록 (정렬된 순서)
+func (c *DUChain) Offsets() []string {
+	var res []string
+	for offset := range c.chain {
+		res = append(res, offset)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
 func (c *DUChain) LookUpUseOfDef(offset string, def int) ([]int, bool) {
 	var res []int
 	isOk := false
@@ -103,7 +103,10 @@ func (g *vfgGenerator) Generate(block cfg.CFGBlock) *DUChain {
 			//g.isLoop = false
 		}
 
-		for _, sil := range b.CodeList() {
+		codeList := b.CodeList()
+		for i, sil := range codeList {
+			// lod, ldi.t, pop2 : pointer 가 가리키는 struct 의 field 주소 계산
+			isFieldAddress := i+2 < len(codeList) && codeList[i+1].Opcode() == icg.Ldi && codeList[i+2].Opcode() == icg.Pop2
 			switch sil.Opcode() {
 			case icg.Str:
 
@@ -117,6 +120,12 @@ func (g *vfgGenerator) Generate(block cfg.CFGBlock) *DUChain {
 				offset := fmt.Sprint(lodSil.Params().Front().Next().Value)
 
 				g.useGen(sil, base, offset)
+				if isFieldAddress {
+					// pointer 가 가리키는 struct 의 field (ex : pointer receiver 의 t.owner) 는 추적하지 않음
+					g.addressOffset = append(g.addressOffset, offset)
+					g.aliasStack = append(g.aliasStack, true)
+					g.addressBase = append(g.addressBase, base)
+				}
 			case icg.Lda:
 				ldaSil := sil.(*icg.StackOpcode)
 				addOffset := fmt.Sprint(ldaSil.Params().Front().Next().Value)
@@ -144,6 +153,10 @@ func (g *vfgGenerator) Generate(block cfg.CFGBlock) *DUChain {
 					g.defGen(sil, addressOffset)
 				}
 			case icg.Ldi:
+				// ldi.t, pop2 : field 주소를 계산하기 위한 struct 의 load (주소는 field 를 읽거나 쓰는 ldi, sti 에서 사용)
+				if i+1 < len(codeList) && codeList[i+1].Opcode() == icg.Pop2 {
+					break
+				}
 				addressOffset := g.addressOffset[len(g.addressOffset)-1]
 				g.addressOffset = g.addressOffset[0 : len(g.addressOffset)-1]
 
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+	"WAH_prototype_go-master/Src/icg/symbolTable"
+	"WAH_prototype_go-master/Src/loader"
+	"WAH_prototype_go-master/Src/wah"
+)
+
+//analysisTarget ...
+// 패키지 하나에 대해 생성한 SIL, CFG, DUChain
+type analysisTarget struct {
+	pkg                *loader.Package
+	strPool            *symbolTable.StringPool
+	litTable           *symbolTable.LiteralTable
+	silTable           *icg.SILTable
+	controlFlowGraphs  map[int]cfg.CFGBlock
+	duChainofFunctions map[int]vfg.DUChain
+	errs               []wah.AnalysisError // SIL, CFG, DUChain 생성 중 발생한 오류 (graph 기반 분석은 수행하지 않음)
+}
+
+// loadTargets ... : 분석 대상을 패키지 단위로 읽고 보안약점 분석을 위한 SIL, CFG, DUChain 생성 (패키지의 모든 파일 대상)
+// SIL, CFG, DUChain 생성 중 panic 이 발생한 패키지는 분석 오류로 기록하고 나머지 패키지를 계속 처리
+func loadTargets(fs *token.FileSet, patterns []string) ([]*analysisTarget, error) {
+	pkgs, err := loader.Load(fs, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*analysisTarget
+	for _, pkg := range pkgs {
+		t := &analysisTarget{pkg: pkg}
+		var err error
+		t.strPool, t.litTable, t.silTable, t.controlFlowGraphs, t.duChainofFunctions, err = wah.GenerateGraphs(fs, pkg.Files, pkg.Info)
+		if err != nil {
+			file := pkg.Dir
+			if len(pkg.Files) > 0 {
+				file = fs.Position(pkg.Files[0].Package).Filename
+			}
+			t.errs = append(t.errs, wah.AnalysisError{File: file, Message: err.Error()})
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// loadConfig ... : -config 로 지정한 설정 파일, 지정하지 않은 경우 현재 디렉토리의 .wah.yaml, .wah.yml, .wah.json 을 읽음
+func loadConfig(path string) (*wah.Config, error) {
+	if path != "" {
+		return wah.LoadConfig(path)
+	}
+	for _, name := range wah.ConfigFileNames {
+		if _, err := os.Stat(name); err == nil {
+			return wah.LoadConfig(name)
+		}
+	}
+	return wah.DefaultConfig(), nil
+}
+
+func printReport(report wah.Report, outputFormat string) error {
+	report.Sort()
+
+	switch outputFormat {
+	case "json":
+		return wah.WriteJSON(os.Stdout, report)
+	case "sarif":
+		return wah.WriteSARIF(os.Stdout, report)
+	case "html":
+		return wah.WriteHTML(os.Stdout, report)
+	}
+	wah.WriteText(os.Stdout, report)
+	return nil
+}
+
+func runAnalyze(args []string) int {
+	flags := newFlagSet("analyze", "<file.go ...|dir|./...>",
+		"Analyze the chaincode packages matched by the arguments with the AST checks and the SIL/CFG/DU chain based checks.")
+	outputFormat := flags.String("o", "text", "output format of the analysis result: \"text\", \"json\", \"sarif\" or \"html\"")
+	baselineFile := flags.String("baseline", "", "do not report the weaknesses recorded in this baseline file and list the fixed ones")
+	writeBaselineFile := flags.String("write-baseline", "", "record the weaknesses found in this run to a baseline file")
+	failOn := flags.String("fail-on", "", "exit with status 1 when a weakness of this severity or higher is reported: \"low\", \"medium\", \"high\" or \"none\" (default: failOn of the config, otherwise none)")
+	configFile := flags.String("config", "", "project configuration file (default: .wah.yaml, .wah.yml or .wah.json in the current directory)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if *outputFormat != "text" && *outputFormat != "json" && *outputFormat != "sarif" && *outputFormat != "html" {
+		fmt.Fprintf(os.Stderr, "wah analyze: the -o option requires one of \"text\", \"json\", \"sarif\" or \"html\", got %q\n", *outputFormat)
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "wah analyze: no analysis target")
+		flags.Usage()
+		return exitUsage
+	}
+
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wah analyze: %v\n", err)
+		return exitError
+	}
+
+	failOnSeverity := config.FailOnSeverity()
+	if *failOn != "" {
+		if failOnSeverity, err = wah.ParseFailOn(*failOn); err != nil {
+			fmt.Fprintf(os.Stderr, "wah analyze: -fail-on: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	fs := token.NewFileSet()
+	targets, err := loadTargets(fs, flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wah analyze: %v\n", err)
+		return exitError
+	}
+
+	var report wah.Report
+	for _, t := range targets {
+		// SIL, CFG, DUChain 을 생성하지 못한 패키지도 AST 기반 분석 결과는 보고
+		pkgReport := wah.AnalysisPackage(fs, t.pkg.Files, t.pkg.Info, t.silTable, t.controlFlowGraphs, t.duChainofFunctions, t.litTable, config)
+		pkgReport.Errors = append(append([]wah.AnalysisError(nil), t.errs...), pkgReport.Errors...)
+		report.Merge(pkgReport)
+	}
+	report.Sort()
+
+	// baseline 에는 이번 실행에서 탐지된 모든 보안약점을 기록 (기존 baseline 적용 전)
+	if *writeBaselineFile != "" {
+		if err := wah.WriteBaseline(*writeBaselineFile, wah.NewBaseline(report.Findings)); err != nil {
+			fmt.Fprintf(os.Stderr, "wah analyze: %v\n", err)
+			return exitError
+		}
+	}
+	if *baselineFile != "" {
+		baseline, err := wah.ReadBaseline(*baselineFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wah analyze: %v\n", err)
+			return exitError
+		}
+		report.ApplyBaseline(baseline)
+	}
+
+	if err := printReport(report, *outputFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "wah analyze: %v\n", err)
+		return exitError
+	}
+
+	// 분석 오류가 있으면 결과가 불완전하므로 보안약점 여부와 관계없이 오류로 종료
+	if len(report.Errors) > 0 {
+		for _, e := range report.Errors {
+			fmt.Fprintf(os.Stderr, "wah analyze: analysis error: %v\n", e)
+		}
+		return exitError
+	}
+	if failOnSeverity != 0 && report.CountAtLeast(failOnSeverity) > 0 {
+		return exitFindings
+	}
+	return exitOK
+}
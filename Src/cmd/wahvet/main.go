@@ -0,0 +1,15 @@
+// wahvet ... : WAH 의 보안약점 분석을 go/analysis driver 로 실행
+//
+//	wahvet ./...                     (단독 실행)
+//	go vet -vettool=$(which wahvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"WAH_prototype_go-master/Src/wahcheck"
+)
+
+func main() {
+	multichecker.Main(wahcheck.Analyzers...)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/astprinter"
+	"WAH_prototype_go-master/Src/icg"
+	"WAH_prototype_go-master/Src/icg/symbolTable"
+)
+
+// functionKeys ... : 함수 key 를 함수 이름 순으로 정렬 (출력 결과를 항상 같은 순서로 유지)
+func functionKeys(strPool *symbolTable.StringPool, keys []int) []int {
+	sort.Slice(keys, func(i, j int) bool {
+		return strPool.LookupSymbolName(keys[i]) < strPool.LookupSymbolName(keys[j])
+	})
+	return keys
+}
+
+func PrintDUChain(duChainofFunctions map[int]vfg.DUChain, strPool *symbolTable.StringPool) {
+	var keys []int
+	for k := range duChainofFunctions {
+		keys = append(keys, k)
+	}
+
+	for _, k := range functionKeys(strPool, keys) {
+		duChain := duChainofFunctions[k]
+		fmt.Println("==============================================")
+		fmt.Printf("%s function\n", strPool.LookupSymbolName(k))
+		fmt.Println("==============================================")
+		fmt.Println("**********************************************")
+		duChain.Print()
+		fmt.Println("**********************************************")
+		fmt.Println()
+	}
+}
+
+func DUChainFileGen(duChainofFunctions map[int]vfg.DUChain, strPool *symbolTable.StringPool, location string) error {
+	for k, duChain := range duChainofFunctions {
+		str := filepath.Join(location, strPool.LookupSymbolName(k)+".du")
+		if err := ioutil.WriteFile(str, []byte(duChain.String()), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PrintCFG(controlFlowGraphs map[int]cfg.CFGBlock, strPool *symbolTable.StringPool) {
+	var keys []int
+	for k := range controlFlowGraphs {
+		keys = append(keys, k)
+	}
+
+	for _, k := range functionKeys(strPool, keys) {
+		fmt.Println("------------------------------")
+		fmt.Printf("\tFunction : %s \n", strPool.LookupSymbolName(k))
+		fmt.Println("------------------------------")
+		fmt.Println(cfg.Print(controlFlowGraphs[k]) + "}")
+		cfg.InitPrinter()
+		fmt.Println()
+	}
+}
+
+func CFGFileGen(controlFlowGraphs map[int]cfg.CFGBlock, strPool *symbolTable.StringPool, location string) error {
+	for k, root := range controlFlowGraphs {
+		printStr := cfg.Print(root) + "}"
+		cfg.InitPrinter()
+
+		str := filepath.Join(location, strPool.LookupSymbolName(k)+".cfg")
+		if err := ioutil.WriteFile(str, []byte(printStr), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func SILFileGen(table *icg.SILTable, location string) error {
+	for k, v := range table.FunctionCodeTable() {
+		buffer := strings.Builder{}
+		for _, info := range v {
+
+			if stackInfo, ok := info.(*icg.StackOpcode); ok {
+				buffer.WriteString("\t" + stackInfo.String() + "\n")
+			}
+			if arithinfo, ok := info.(*icg.ArithmeticOpcode); ok {
+				buffer.WriteString("\t" + arithinfo.String() + "\n")
+			}
+			if cinfo, ok := info.(*icg.ControlOpcode); ok {
+				if cinfo.Opcode() == icg.Label {
+					buffer.WriteString(cinfo.String() + "\n")
+				} else {
+					buffer.WriteString("\t" + cinfo.String() + "\n")
+				}
+			}
+
+		}
+		buffer.WriteString("\n")
+
+		str := filepath.Join(location, table.StringPool().LookupSymbolName(k)+".sil")
+		if err := ioutil.WriteFile(str, []byte(buffer.String()), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PrintSIL(table *icg.SILTable) {
+	var keys []int
+	for k := range table.FunctionCodeTable() {
+		keys = append(keys, k)
+	}
+
+	buffer := strings.Builder{}
+	for _, k := range functionKeys(table.StringPool(), keys) {
+		buffer.WriteString("------------------------------\n")
+		buffer.WriteString(fmt.Sprintf("\tFunction : %s \n", table.StringPool().LookupSymbolName(k)))
+		buffer.WriteString("------------------------------\n")
+
+		for _, info := range table.FunctionCodeTable()[k] {
+
+			if stackInfo, ok := info.(*icg.StackOpcode); ok {
+				buffer.WriteString("\t" + strconv.Itoa(stackInfo.GetLine()) + ": " + stackInfo.String() + "\n")
+			}
+			if arithinfo, ok := info.(*icg.ArithmeticOpcode); ok {
+				buffer.WriteString("\t" + strconv.Itoa(arithinfo.GetLine()) + ": " + arithinfo.String() + "\n")
+			}
+			if cinfo, ok := info.(*icg.ControlOpcode); ok {
+				if cinfo.Opcode() == icg.Label {
+					buffer.WriteString(strconv.Itoa(cinfo.GetLine()) + ": " + cinfo.String() + "\n")
+				} else {
+					buffer.WriteString("\t" + strconv.Itoa(cinfo.GetLine()) + ": " + cinfo.String() + "\n")
+				}
+			}
+
+		}
+		buffer.WriteString("\n")
+	}
+
+	fmt.Println(buffer.String())
+}
+
+func runDump(args []string) int {
+	flags := newFlagSet("dump", "ast|sil|cfg|du <file.go ...|dir|./...>",
+		"Print the AST, SIL, CFG (graphviz dot) or DU chain of the analysis target.\n"+
+			"With -out, the SIL, CFG or DU chain of each function is written to <dir>/<function>.sil|.cfg|.du instead.")
+	outDir := flags.String("out", "", "directory to write one file per function (sil, cfg and du only)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "wah dump: a dump kind and an analysis target are required")
+		flags.Usage()
+		return exitUsage
+	}
+
+	kind := strings.ToLower(flags.Arg(0))
+	switch kind {
+	case "ast":
+		if *outDir != "" {
+			fmt.Fprintln(os.Stderr, "wah dump: the AST can only be printed, -out is not supported")
+			return exitUsage
+		}
+	case "sil", "cfg", "du":
+	default:
+		fmt.Fprintf(os.Stderr, "wah dump: unknown dump kind %q (expected ast, sil, cfg or du)\n", flags.Arg(0))
+		return exitUsage
+	}
+
+	fs := token.NewFileSet()
+	targets, err := loadTargets(fs, flags.Args()[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wah dump: %v\n", err)
+		return exitError
+	}
+
+	if *outDir != "" {
+		if err := os.MkdirAll(*outDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "wah dump: %v\n", err)
+			return exitError
+		}
+	}
+
+	status := exitOK
+	for _, t := range targets {
+		// SIL, CFG, DUChain 을 생성하지 못한 패키지는 AST 만 출력할 수 있음
+		if kind != "ast" && len(t.errs) > 0 {
+			for _, e := range t.errs {
+				fmt.Fprintf(os.Stderr, "wah dump: %v\n", e)
+			}
+			status = exitError
+			continue
+		}
+
+		var err error
+		switch kind {
+		case "ast":
+			for _, f := range t.pkg.Files {
+				astprinter.PrintAst(f)
+			}
+		case "sil":
+			if *outDir != "" {
+				err = SILFileGen(t.silTable, *outDir)
+			} else {
+				PrintSIL(t.silTable)
+			}
+		case "cfg":
+			if *outDir != "" {
+				err = CFGFileGen(t.controlFlowGraphs, t.strPool, *outDir)
+			} else {
+				PrintCFG(t.controlFlowGraphs, t.strPool)
+			}
+		case "du":
+			if *outDir != "" {
+				err = DUChainFileGen(t.duChainofFunctions, t.strPool, *outDir)
+			} else {
+				PrintDUChain(t.duChainofFunctions, t.strPool)
+			}
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wah dump: %v\n", err)
+			return exitError
+		}
+	}
+	return status
+}
@@ -22,5 +22,7 @@ require (
 	go.uber.org/zap v1.17.0 // indirect
 	golang.org/x/crypto v0.0.0-20210513164829-c07d793c2f9a // indirect
 	golang.org/x/net v0.0.0-20210525063256-abc453219eb5 // indirect
+	golang.org/x/tools v0.1.1
 	google.golang.org/grpc v1.38.0 // indirect
+	gopkg.in/yaml.v2 v2.4.0
 )
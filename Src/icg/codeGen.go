@@ -47,11 +47,22 @@ func (s Statements) String() string {
 
 //CodeGen ...
 func CodeGen(f *ast.File, fs *token.FileSet, info *types.Info, pool *symbolTable.StringPool,
+	symTble *symbolTable.BlockSymbolTable, litTable *symbolTable.LiteralTable) *SILTable {
+	return CodeGenFiles([]*ast.File{f}, fs, info, pool, symTble, litTable)
+}
+
+//CodeGenFiles ...
+// 하나의 패키지를 구성하는 파일들을 하나의 SILTable 로 생성
+// (string pool 은 StringPoolGenerator.GenFiles 로 같은 순서의 파일 목록에서 생성되어야 함)
+func CodeGenFiles(files []*ast.File, fs *token.FileSet, info *types.Info, pool *symbolTable.StringPool,
 	symTble *symbolTable.BlockSymbolTable, litTable *symbolTable.LiteralTable) *SILTable {
 	var icg *ICG = &ICG{}
 	icg.Init(fs, pool, info, symTble, litTable)
 
-	silTable := icg.Visit(f)
+	var silTable *SILTable
+	for _, f := range files {
+		silTable = icg.Visit(f)
+	}
 	for _, codeInfoList := range silTable.FunctionCodeTable() {
 		for i, sil := range codeInfoList {
 			sil.SetLine(i)
@@ -679,6 +690,7 @@ func (icg *ICG) Visit(node ast.Node) *SILTable {
 								stName = sel.Sel.Name
 							}
 							opcode := &StackOpcode{Lod, P, list.New(), icg._codeState, -1, false, -1, -1, false, position.Line}
+							opcode._isReceiver = stInfo.IsReceiver
 							opcode._params.PushBack(stInfo.Base)
 							if stInfo.Base == 0 {
 								opcode._params.PushBack("$" + stName)
@@ -774,13 +786,6 @@ func (icg *ICG) Visit(node ast.Node) *SILTable {
 				}
 			}
 
-			// X is Package ( package.Sel)
-			if info == nil {
-				typeInfo := icg._info.Types[n.Sel].Type
-
-				fmt.Println(typeInfo)
-			}
-
 		}
 
 	case *ast.IndexExpr:
@@ -800,8 +805,6 @@ func (icg *ICG) Visit(node ast.Node) *SILTable {
 			icg._codeInfoList = append(icg._codeInfoList, opcode)
 
 		} else {
-			typeInfo := icg._info.Types[n.X].Type
-			fmt.Println(typeInfo)
 			icg.Visit(n.X)
 		}
 
@@ -1031,7 +1034,6 @@ func (icg *ICG) Visit(node ast.Node) *SILTable {
 
 		callOp.Init()
 
-		//fmt.Println(icg._info.Types[n.Fun].Type.(*types.Signature))
 		if sig, ok := icg._info.Types[n.Fun].Type.(*types.Signature); ok {
 			callOp._pushParamNum = sig.Results().Len()
 		} else {
@@ -3734,6 +3736,7 @@ func (icg *ICG) Visit(node ast.Node) *SILTable {
 		icg._codeInfoList = append([]CodeInfo{opcode}, icg._codeInfoList...)
 		icg._funcDeclVarSize = 0
 		icg._silTable.Insert(funcKey, icg._codeInfoList)
+		icg._silTable.SetFunctionFile(funcKey, position.Filename)
 
 		icg._codeInfoList = []CodeInfo{}
 	// Files and packages
@@ -351,12 +351,14 @@ func (code *ControlOpcode) String() string {
 // SILTable ...
 type SILTable struct {
 	_FunctionCodeTable map[int][]CodeInfo
+	_FunctionFileTable map[int]string // fKey, 함수가 선언된 파일
 	_Mfkey             int
 	_Pool              *symbolTable.StringPool
 }
 
 func (tble *SILTable) Init(pool *symbolTable.StringPool) {
 	tble._FunctionCodeTable = make(map[int][]CodeInfo)
+	tble._FunctionFileTable = make(map[int]string)
 	tble._Pool = pool
 }
 
@@ -370,6 +372,15 @@ func (tble *SILTable) Insert(fKey int, codeList []CodeInfo) {
 		tble._Mfkey = fKey
 	}
 }
+func (tble *SILTable) SetFunctionFile(fKey int, fileName string) {
+	if tble._FunctionFileTable == nil {
+		tble._FunctionFileTable = make(map[int]string)
+	}
+	tble._FunctionFileTable[fKey] = fileName
+}
+func (tble *SILTable) FunctionFile(fKey int) string {
+	return tble._FunctionFileTable[fKey]
+}
 func (tble *SILTable) IsExist(fKey int) bool {
 	if len := len(tble._FunctionCodeTable[fKey]); len > 0 {
 		return true
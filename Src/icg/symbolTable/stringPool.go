@@ -257,6 +257,28 @@ func (pg *StringPoolGenerator) GenDeclList(list []ast.Decl) {
 		pg.Gen(x)
 	}
 }
+// GenFiles ...
+// 하나의 패키지를 구성하는 파일들의 string pool 생성
+// 다른 파일에 선언된 전역 변수도 전역 블록 (0) 에 등록되도록 모든 파일의 GenDecl 을 먼저 처리
+func (pg *StringPoolGenerator) GenFiles(files []*ast.File) (*StringPool, *BlockSymbolTable, *LiteralTable) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok {
+				pg.Gen(genDecl)
+			}
+		}
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			_, ok := decl.(*ast.GenDecl)
+			if !ok {
+				pg.Gen(decl)
+			}
+		}
+	}
+
+	return pg._pool, pg.offsetTable, pg.literalTable
+}
 func (pg *StringPoolGenerator) Gen(node ast.Node) (*StringPool, *BlockSymbolTable, *LiteralTable) {
 	switch n := node.(type) {
 	// Comments and fields
@@ -0,0 +1,147 @@
+package loader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//Package ...
+// 하나의 디렉토리에 있는 분석 대상 파일들을 하나의 패키지로 타입 검사한 결과
+type Package struct {
+	Path      string
+	Dir       string
+	FileNames []string
+	Files     []*ast.File
+	Info      *types.Info
+	Types     *types.Package
+}
+
+//Load ...
+// 분석 대상 (파일 목록, 디렉토리 또는 "./..." 패턴) 을 패키지 단위로 읽어 타입 검사
+// 모든 패키지는 하나의 FileSet 을 공유
+func Load(fs *token.FileSet, patterns []string) ([]*Package, error) {
+	var dirs []string
+	dirFiles := make(map[string][]string)
+
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasSuffix(pattern, ".go"):
+			dir := filepath.Dir(pattern)
+			if _, ok := dirFiles[dir]; !ok {
+				dirs = append(dirs, dir)
+			}
+			dirFiles[dir] = append(dirFiles[dir], pattern)
+		case pattern == "..." || strings.HasSuffix(pattern, "/..."):
+			root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+			if root == "" {
+				root = "."
+			}
+			found, err := walkPackageDirs(root)
+			if err != nil {
+				return nil, err
+			}
+			for _, dir := range found {
+				if _, ok := dirFiles[dir]; !ok {
+					dirs = append(dirs, dir)
+					dirFiles[dir] = nil
+				}
+			}
+		default:
+			if _, ok := dirFiles[pattern]; !ok {
+				dirs = append(dirs, pattern)
+				dirFiles[pattern] = nil
+			}
+		}
+	}
+
+	var res []*Package
+	for _, dir := range dirs {
+		pkg, err := loadDir(fs, dir, dirFiles[dir])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pkg)
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no Go files matched %s", strings.Join(patterns, " "))
+	}
+	return res, nil
+}
+
+// walkPackageDirs ... : root 아래에서 Go 파일을 포함하는 디렉토리 목록 (vendor, testdata, ., _ 로 시작하는 디렉토리 제외)
+func walkPackageDirs(root string) ([]string, error) {
+	var res []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+
+		if _, err := build.ImportDir(path, 0); err == nil {
+			res = append(res, path)
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func loadDir(fs *token.FileSet, dir string, fileNames []string) (*Package, error) {
+	pkg := &Package{Dir: dir, Path: dir}
+
+	if fileNames == nil {
+		buildPkg, err := build.ImportDir(dir, 0)
+		if err != nil {
+			return nil, fmt.Errorf("could not load %s: %v", dir, err)
+		}
+		for _, name := range buildPkg.GoFiles {
+			fileNames = append(fileNames, filepath.Join(dir, name))
+		}
+		if buildPkg.ImportPath != "" && buildPkg.ImportPath != "." {
+			pkg.Path = buildPkg.ImportPath
+		}
+	}
+	sort.Strings(fileNames)
+
+	for _, fileName := range fileNames {
+		f, err := parser.ParseFile(fs, fileName, nil, parser.AllErrors|parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %v", fileName, err)
+		}
+		pkg.FileNames = append(pkg.FileNames, fileName)
+		pkg.Files = append(pkg.Files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fs, "source", nil)}
+	pkg.Info = &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+
+	typesPkg, err := conf.Check(pkg.Path, fs, pkg.Files, pkg.Info)
+	if err != nil {
+		return nil, err // type error
+	}
+	pkg.Types = typesPkg
+
+	return pkg, nil
+}
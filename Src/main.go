@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	"WAH_prototype_go-master/Src/wah"
+)
+
+const version = "0.2.0"
+
+// 종료 코드
+// 0 : 정상 종료, 1 : -fail-on 기준 이상의 보안약점 탐지, 2 : 잘못된 명령어 또는 옵션,
+// 3 : 분석 대상 또는 설정 파일 로드 실패, 분석 오류, 내부 오류
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitUsage    = 2
+	exitError    = 3
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"analyze", "Analyze chaincode packages and report weaknesses", runAnalyze},
+		{"dump", "Print or write the AST, SIL, CFG or DU chain of the analysis target", runDump},
+		{"rules", "List the chaincode weaknesses (CCW) detected by WAH", runRules},
+		{"version", "Print the WAH version", runVersion},
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "WAH : Weakness Analyzer for Hyperledger fabric chaincode")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage : wah <command> [options] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands :")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "\t%-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run \"wah <command> -h\" for the options of each command.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit status :")
+	fmt.Fprintln(w, "\t0 : success")
+	fmt.Fprintln(w, "\t1 : weaknesses at or above the -fail-on severity were reported")
+	fmt.Fprintln(w, "\t2 : invalid command, option or argument")
+	fmt.Fprintln(w, "\t3 : the analysis target or a file given by an option could not be read, or an analysis or internal error occurred")
+}
+
+// newFlagSet ... : 하위 명령어의 옵션 파서 생성 (-h 입력 시 사용법 출력)
+func newFlagSet(name string, args string, description string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		w := flags.Output()
+		fmt.Fprintf(w, "Usage : wah %s [options] %s\n\n", name, args)
+		fmt.Fprintf(w, "%s\n", description)
+
+		hasOption := false
+		flags.VisitAll(func(*flag.Flag) { hasOption = true })
+		if hasOption {
+			fmt.Fprintln(w, "\nOptions :")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+// parseFlags ... : 옵션 파싱 결과를 종료 코드로 변환 (-h 는 정상 종료)
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+func runRules(args []string) int {
+	flags := newFlagSet("rules", "", "List every chaincode weakness with its identifier, severity and description.\n"+
+		"The severity and the disabled rules follow the project configuration file.")
+	configFile := flags.String("config", "", "project configuration file (default: .wah.yaml, .wah.yml or .wah.json in the current directory)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wah rules: %v\n", err)
+		return exitError
+	}
+
+	for _, ccw := range wah.CCWList() {
+		status := ""
+		if !config.IsEnabled(ccw) {
+			status = " (disabled)"
+		}
+		fmt.Printf("%s  %-30s %-8s %s%s\n", ccw.ID(), ccw.String(), config.Severity(ccw), ccw.Description(), status)
+	}
+	return exitOK
+}
+
+func runVersion(args []string) int {
+	flags := newFlagSet("version", "", "Print the WAH version.")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	fmt.Printf("wah version %s\n", version)
+	return exitOK
+}
+
+func run(args []string) (code int) {
+	// 분석기 내부에서 발생한 panic 은 내부 오류 종료 코드로 처리
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "wah: internal error: %v\n%s", r, debug.Stack())
+			code = exitError
+		}
+	}()
+
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		usage(os.Stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "wah: unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
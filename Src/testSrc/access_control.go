@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type OpenTransfer struct {
+}
+
+func (t *OpenTransfer) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *OpenTransfer) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, args := stub.GetFunctionAndParameters()
+	if fn == "transfer" && len(args) == 2 {
+		return t.transfer(stub, args[0], args[1])
+	}
+	return shim.Error("unknown function")
+}
+
+// 누구나 호출할 수 있는 트랜잭션에서 client identity 를 확인하지 않고 소유자를 바꿈
+func (t *OpenTransfer) transfer(stub shim.ChaincodeStubInterface, asset string, owner string) peer.Response {
+	stub.PutState(asset, []byte(owner))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(OpenTransfer))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type CheckedTransfer struct {
+}
+
+func (t *CheckedTransfer) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *CheckedTransfer) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, args := stub.GetFunctionAndParameters()
+	if fn == "transfer" && len(args) == 2 {
+		return t.transfer(stub, args[0], args[1])
+	}
+	if fn == "query" && len(args) == 1 {
+		value, _ := stub.GetState(args[0])
+		return shim.Success(value)
+	}
+	return shim.Error("unknown function")
+}
+
+func (t *CheckedTransfer) transfer(stub shim.ChaincodeStubInterface, asset string, owner string) peer.Response {
+	mspID, err := cid.GetMSPID(stub)
+	if err != nil || mspID != "Org1MSP" {
+		return shim.Error("only Org1MSP can transfer assets")
+	}
+	stub.PutState(asset, []byte(owner))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(CheckedTransfer))
+}
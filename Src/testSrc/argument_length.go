@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type ArgumentIndex struct {
+}
+
+func (t *ArgumentIndex) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *ArgumentIndex) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	_, args := stub.GetFunctionAndParameters()
+
+	// 인자 개수를 확인하지 않고 접근하면 인자가 부족할 때 index out of range 로 panic
+	key := args[0]
+	value := args[1]
+	stub.PutState(key, []byte(value))
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(ArgumentIndex))
+}
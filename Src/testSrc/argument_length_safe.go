@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type ArgumentCount struct {
+}
+
+func (t *ArgumentCount) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *ArgumentCount) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	_, args := stub.GetFunctionAndParameters()
+	if len(args) != 2 {
+		return shim.Error("expecting a key and a value")
+	}
+
+	key := args[0]
+	value := args[1]
+	stub.PutState(key, []byte(value))
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(ArgumentCount))
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type CrossChaincode struct {
+}
+
+func (t *CrossChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *CrossChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := [][]byte{[]byte("query"), []byte("a")}
+
+	// 다른 channel 의 chaincode 를 호출하면 그 chaincode 의 쓰기는 버려지고 읽기는 검증되지 않음
+	res := stub.InvokeChaincode("asset", args, "otherchannel")
+	stub.PutState("a", res.Payload)
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(CrossChaincode))
+}
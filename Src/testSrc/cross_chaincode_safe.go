@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type LocalQuery struct {
+}
+
+func (t *LocalQuery) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+// invokeChaincode 는 다른 chaincode 를 호출하지 않는 같은 chaincode 의 함수
+func (t *LocalQuery) invokeChaincode(stub shim.ChaincodeStubInterface, key string) []byte {
+	value, _ := stub.GetState(key)
+	return value
+}
+
+func (t *LocalQuery) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	value := t.invokeChaincode(stub, "a")
+	stub.PutState("b", value)
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(LocalQuery))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type DuplicateWrite struct {
+}
+
+func (t *DuplicateWrite) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *DuplicateWrite) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	key := "balance"
+	stub.PutState(key, []byte("100"))
+
+	// 같은 transaction 에서 같은 key 에 다시 쓰면 처음 쓴 값은 기록되지 않음
+	stub.PutState(key, []byte("200"))
+
+	return shim.Success(nil)
+}
+
+func (t *DuplicateWrite) Close(stub shim.ChaincodeStubInterface) peer.Response {
+	owner := "owner"
+	stub.PutState(owner, []byte("closed"))
+	stub.DelState("owner")
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(DuplicateWrite))
+}
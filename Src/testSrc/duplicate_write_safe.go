@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type DistinctWrite struct {
+}
+
+func (t *DistinctWrite) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *DistinctWrite) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	// 같은 변수라도 다시 대입한 key 는 다른 key
+	key := "a"
+	stub.PutState(key, []byte("100"))
+	key = "b"
+	stub.PutState(key, []byte("200"))
+	stub.PutState("c", []byte("300"))
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(DistinctWrite))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"io/ioutil"
+	web "net/http"
+	"os"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PriceFeed struct {
+}
+
+func (t *PriceFeed) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PriceFeed) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	// 외부 서비스, peer 의 파일과 환경 변수는 endorsing peer 마다 다를 수 있음
+	resp, err := web.Get("http://example.com/price")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	rate, err := ioutil.ReadFile("/etc/rate")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	region := os.Getenv("REGION")
+
+	stub.PutState(region, append([]byte(resp.Status), rate...))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(PriceFeed))
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type LedgerFeed struct {
+}
+
+func (t *LedgerFeed) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *LedgerFeed) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	// 문자열에 "time", "os/exec", "net/http" 가 있어도 호출이 아니므로 보고하지 않음
+	fmt.Println("update time: price is read from the ledger, not from net/http or os/exec")
+
+	price, err := stub.GetState("price")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	stub.PutState("last_price", price)
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(LedgerFeed))
+}
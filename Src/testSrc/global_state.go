@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+var issued int
+
+type Counter struct {
+	lastIssued int
+}
+
+func (t *Counter) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *Counter) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, args := stub.GetFunctionAndParameters()
+	if fn == "issue" && len(args) == 1 {
+		return t.issue(stub, args[0])
+	}
+	if fn == "count" {
+		return t.count(stub)
+	}
+	return shim.Error("unknown function")
+}
+
+// 전역 변수와 receiver field 는 transaction 을 실행한 peer 의 memory 에만 남음
+func (t *Counter) issue(stub shim.ChaincodeStubInterface, owner string) peer.Response {
+	issued = issued + 1
+	stub.PutState("token"+strconv.Itoa(issued), []byte(owner))
+	t.lastIssued = issued
+	return shim.Success(nil)
+}
+
+func (t *Counter) count(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success([]byte(strconv.Itoa(issued) + "/" + strconv.Itoa(t.lastIssued)))
+}
+
+func main() {
+	shim.Start(new(Counter))
+}
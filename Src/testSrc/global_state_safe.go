@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+const tokenPrefix = "token"
+
+type LedgerCounter struct {
+}
+
+func (t *LedgerCounter) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *LedgerCounter) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, args := stub.GetFunctionAndParameters()
+	if fn == "issue" && len(args) == 1 {
+		return t.issue(stub, args[0])
+	}
+	return shim.Error("unknown function")
+}
+
+// 발행한 token 의 수는 ledger 에 기록
+func (t *LedgerCounter) issue(stub shim.ChaincodeStubInterface, owner string) peer.Response {
+	value, err := stub.GetState("issued")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	issued, _ := strconv.Atoi(string(value))
+	issued = issued + 1
+	stub.PutState("issued", []byte(strconv.Itoa(issued)))
+	stub.PutState(tokenPrefix+strconv.Itoa(issued), []byte(owner))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(LedgerCounter))
+}
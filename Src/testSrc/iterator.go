@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type IteratorLeak struct {
+}
+
+func (t *IteratorLeak) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *IteratorLeak) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	results, err := stub.GetQueryResult(`{"selector":{"owner":"alice"}}`)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// 결과가 있으면 iterator 를 닫지 않고 반환
+	if results.HasNext() {
+		return shim.Success([]byte("found"))
+	}
+	results.Close()
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(IteratorLeak))
+}
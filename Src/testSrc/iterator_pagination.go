@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PaginationLeak struct {
+}
+
+func (t *PaginationLeak) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PaginationLeak) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	assets, _, err := stub.GetStateByRangeWithPagination("asset0", "asset9", 10, "")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// 결과가 있으면 iterator 를 닫지 않고 반환
+	if assets.HasNext() {
+		return shim.Success([]byte("found"))
+	}
+	assets.Close()
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(PaginationLeak))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type IteratorClose struct {
+}
+
+func (t *IteratorClose) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *IteratorClose) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	results, err := stub.GetQueryResult(`{"selector":{"owner":"alice"}}`)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer results.Close()
+
+	if results.HasNext() {
+		return shim.Success([]byte("found"))
+	}
+
+	return shim.Success(nil)
+}
+
+func (t *IteratorClose) Count(stub shim.ChaincodeStubInterface) peer.Response {
+	history, err := stub.GetHistoryForKey("asset1")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	for history.HasNext() {
+		history.Next()
+	}
+	history.Close()
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(IteratorClose))
+}
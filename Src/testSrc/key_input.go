@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type AssetRegistry struct {
+}
+
+func (t *AssetRegistry) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *AssetRegistry) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := stub.GetStringArgs()
+	if len(args) != 2 {
+		return shim.Error("register needs an id and a value")
+	}
+
+	// id 에 다른 entity 의 prefix 나 composite key 구분자가 있으면 다른 entity 를 덮어씀
+	key := "asset_" + args[0]
+	stub.PutState(key, []byte(args[1]))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(AssetRegistry))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type BranchRegistry struct {
+}
+
+func (t *BranchRegistry) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *BranchRegistry) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := stub.GetStringArgs()
+	if len(args) != 3 {
+		return shim.Error("register needs a function, an id and a value")
+	}
+
+	// 빈 문자열과의 비교는 id 의 내용을 검증하지 않음
+	id := args[1]
+	if id == "" {
+		return shim.Error("empty id")
+	}
+
+	if args[0] == "update" {
+		if strings.ContainsAny(id, "\x00_") {
+			return shim.Error("invalid id")
+		}
+		stub.PutState("asset_"+id, []byte(args[2]))
+	} else {
+		// id 는 update 분기에서만 검증됨
+		stub.PutState("asset_"+id, []byte(args[2]))
+	}
+	stub.DelState("pending_" + id)
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(BranchRegistry))
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type CheckedRegistry struct {
+}
+
+func (t *CheckedRegistry) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *CheckedRegistry) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := stub.GetStringArgs()
+	if len(args) != 2 {
+		return shim.Error("register needs an id and a value")
+	}
+
+	id := args[0]
+	if strings.ContainsAny(id, "\x00_") {
+		return shim.Error("invalid id")
+	}
+	stub.PutState("asset_"+id, []byte(args[1]))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(CheckedRegistry))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type GobAsset struct {
+	ID     string
+	Owners map[string]int
+}
+
+type MapEncoder struct {
+}
+
+func (t *MapEncoder) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *MapEncoder) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	asset := GobAsset{ID: "asset1", Owners: map[string]int{"alice": 60, "bob": 40}}
+
+	// gob 은 map 의 key 순서를 보장하지 않으므로 peer 마다 다른 값이 기록될 수 있음
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(asset); err != nil {
+		return shim.Error(err.Error())
+	}
+	stub.PutState(asset.ID, buf.Bytes())
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(MapEncoder))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type JSONAsset struct {
+	ID     string
+	Owners map[string]int
+}
+
+type MapJSON struct {
+}
+
+func (t *MapJSON) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *MapJSON) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	asset := JSONAsset{ID: "asset1", Owners: map[string]int{"alice": 60, "bob": 40}}
+
+	// encoding/json 은 map 의 key 를 정렬하여 직렬화
+	value, err := json.Marshal(asset)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	stub.PutState(asset.ID, value)
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(MapJSON))
+}
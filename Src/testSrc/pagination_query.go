@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PaginationQuery struct {
+}
+
+func (t *PaginationQuery) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PaginationQuery) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	// 페이지 단위 rich query 도 validation 시점에 재실행되지 않음
+	results, _, err := stub.GetQueryResultWithPagination(`{"selector":{"owner":"alice"}}`, 10, "")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer results.Close()
+
+	assets, _, err := stub.GetStateByRangeWithPagination("asset0", "asset9", 10, "")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer assets.Close()
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(PaginationQuery))
+}
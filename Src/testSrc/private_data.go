@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PrivateLeak struct {
+}
+
+func (t *PrivateLeak) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PrivateLeak) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	price, err := stub.GetPrivateData("pricing", "asset1")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// private data 를 world state 에 기록하면 collection 에 속하지 않은 peer 에도 공개됨
+	copied := price
+	stub.PutState("asset1_price", copied)
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(PrivateLeak))
+}
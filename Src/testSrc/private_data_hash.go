@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PrivateHashEvent struct {
+}
+
+func (t *PrivateHashEvent) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PrivateHashEvent) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	hash, err := stub.GetPrivateDataHash("pricing", "asset1")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// 값이 적은 private data 의 hash 는 모든 peer 가 받는 event 로 공개하면 값을 추측할 수 있음
+	stub.SetEvent("priced", hash)
+
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(PrivateHashEvent))
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PrivateCopy struct {
+}
+
+func (t *PrivateCopy) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PrivateCopy) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	price, err := stub.GetPrivateData("pricing", "asset1")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// 같은 collection 에 기록하면 collection 에 속한 peer 에만 공개됨
+	stub.PutPrivateData("pricing", "asset1_copy", price)
+
+	return shim.Success([]byte("ok"))
+}
+
+func main() {
+	shim.Start(new(PrivateCopy))
+}
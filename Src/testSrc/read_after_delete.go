@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type DeleteAndRead struct {
+}
+
+func (t *DeleteAndRead) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *DeleteAndRead) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	key := "asset"
+	if err := stub.DelState(key); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// DelState 는 commit 될 때 반영되므로 삭제한 key 의 이전 값이 읽힘
+	value, err := stub.GetState(key)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(value)
+}
+
+func main() {
+	shim.Start(new(DeleteAndRead))
+}
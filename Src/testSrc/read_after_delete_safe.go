@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type DeleteOrRead struct {
+}
+
+func (t *DeleteOrRead) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *DeleteOrRead) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, _ := stub.GetFunctionAndParameters()
+	key := "asset"
+
+	// DelState 와 GetState 는 서로 다른 분기에서 호출되므로 같은 transaction 에서 실행되지 않음
+	if fn == "delete" {
+		if err := stub.DelState(key); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	}
+
+	value, err := stub.GetState(key)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(value)
+}
+
+func main() {
+	shim.Start(new(DeleteOrRead))
+}
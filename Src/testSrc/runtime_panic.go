@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type PanicTransfer struct {
+}
+
+func (t *PanicTransfer) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *PanicTransfer) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	_, args := stub.GetFunctionAndParameters()
+	if len(args) != 2 {
+		log.Fatal("transfer needs an asset and an amount")
+	}
+	amount := mustAmount(args[1])
+
+	var value interface{} = args[0]
+	asset := value.(string)
+	stub.PutState(asset, []byte(strconv.Itoa(amount)))
+	return shim.Success(nil)
+}
+
+// return 없이 panic 으로 끝나는 함수
+func mustAmount(arg string) int {
+	if amount, err := strconv.Atoi(arg); err == nil {
+		return amount
+	}
+	panic("amount is not a number")
+}
+
+func main() {
+	shim.Start(new(PanicTransfer))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type ErrorTransfer struct {
+}
+
+func (t *ErrorTransfer) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *ErrorTransfer) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	_, args := stub.GetFunctionAndParameters()
+	if len(args) != 2 {
+		return shim.Error("transfer needs an asset and an amount")
+	}
+	amount, err := strconv.Atoi(args[1])
+	if err != nil {
+		return shim.Error("amount is not a number")
+	}
+
+	var value interface{} = args[0]
+	asset, ok := value.(string)
+	if !ok {
+		return shim.Error("asset is not a string")
+	}
+	balances := map[string]int{}
+	balances[asset] = amount
+	stub.PutState(asset, []byte(strconv.Itoa(balances[asset])))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(ErrorTransfer))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type TxTimeChaincode struct {
+}
+
+func (t *TxTimeChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *TxTimeChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	// 로그에만 쓰는 system time 은 ledger 에 영향을 주지 않음
+	start := time.Now()
+
+	// ledger 에는 모든 peer 에서 같은 transaction timestamp 를 기록
+	ts, err := stub.GetTxTimestamp()
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if err := stub.PutState("created", []byte(ts.String())); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	log.Println("invoke took", time.Since(start))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(TxTimeChaincode))
+}
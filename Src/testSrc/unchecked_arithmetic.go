@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type Withdraw struct {
+}
+
+func (t *Withdraw) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *Withdraw) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := stub.GetStringArgs()
+	if len(args) != 2 {
+		return shim.Error("withdraw needs an account and an amount")
+	}
+	balanceBytes, err := stub.GetState(args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	balance, _ := strconv.Atoi(string(balanceBytes))
+	amount, _ := strconv.Atoi(args[1])
+
+	// amount 가 음수이거나 balance 보다 크면 잔액이 늘어나거나 음수가 됨
+	balance = balance - amount
+	stub.PutState(args[0], []byte(strconv.Itoa(balance)))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(Withdraw))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type CheckedWithdraw struct {
+}
+
+func (t *CheckedWithdraw) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+func (t *CheckedWithdraw) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	args := stub.GetStringArgs()
+	if len(args) != 2 {
+		return shim.Error("withdraw needs an account and an amount")
+	}
+	balanceBytes, err := stub.GetState(args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	balance, _ := strconv.Atoi(string(balanceBytes))
+	amount, _ := strconv.Atoi(args[1])
+	if amount <= 0 || balance < amount {
+		return shim.Error("insufficient balance")
+	}
+
+	balance = balance - amount
+	stub.PutState(args[0], []byte(strconv.Itoa(balance)))
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(CheckedWithdraw))
+}
@@ -0,0 +1,245 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// ACAnalyzer ...
+// 트랜잭션 진입 함수 (Invoke, contract 의 트랜잭션 함수) 와 진입 함수에서 호출되는 함수 중 client identity (GetCreator, cid, ClientIdentity) 를 확인하지 않고
+// ledger 에 쓰는 (PutState, DelState, PutPrivateData) 함수 탐지 (MISSING_ACCESS_CONTROL)
+// identity 를 확인하는 호출의 결과로 분기하는 if, switch 문 이후의 코드는 확인된 것으로 봄 (함수 단위로 AST 의 문장 순서를 따라 분석)
+type ACAnalyzer struct {
+	fs            *token.FileSet
+	info          *types.Info
+	decls         map[*types.Func]*ast.FuncDecl
+	checks        map[*types.Func]bool         // identity 를 확인하는 helper 함수 (ex : checkAdmin)
+	writes        map[*types.Func]*ledgerWrite // identity 확인 없이 도달하는 ledger 쓰기 (nil 이면 없음)
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+// ledgerWrite ... : identity 확인 없이 도달하는 ledger 쓰기 호출 (call 은 funcName 함수의 호출)
+type ledgerWrite struct {
+	call     *ast.CallExpr
+	funcName string
+	callee   *types.Func // 다른 함수를 통해 쓰는 경우 그 함수
+}
+
+func (analyzer *ACAnalyzer) Init(fs *token.FileSet, info *types.Info) {
+	analyzer.fs = fs
+	analyzer.info = info
+	analyzer.checks = make(map[*types.Func]bool)
+	analyzer.writes = make(map[*types.Func]*ledgerWrite)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// Findings ...
+func (analyzer *ACAnalyzer) Findings() []Finding {
+	return analyzer.findings
+}
+
+func (analyzer *ACAnalyzer) isWrite(call *ast.CallExpr) bool {
+	funcName := icg.NodeString(analyzer.fs, call.Fun)
+	return matchFunc(funcName, analyzer.config.PutState) || matchFunc(funcName, analyzer.config.DelState) || matchFunc(funcName, analyzer.config.PrivateDataWrites)
+}
+
+// isIdentityCheck ... : identity 를 확인하는 호출 (config.IdentityChecks 또는 그 호출을 포함하는 패키지 안의 함수)
+func (analyzer *ACAnalyzer) isIdentityCheck(call *ast.CallExpr) bool {
+	if matchFunc(icg.NodeString(analyzer.fs, call.Fun), analyzer.config.IdentityChecks) {
+		return true
+	}
+	f := calledFunc(call, analyzer.info)
+	decl, ok := analyzer.decls[f]
+	if !ok {
+		return false
+	}
+	if checked, ok := analyzer.checks[f]; ok {
+		return checked
+	}
+
+	analyzer.checks[f] = false // 재귀 호출
+	checked := false
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok && !checked {
+			checked = analyzer.isIdentityCheck(call)
+		}
+		return !checked
+	})
+	analyzer.checks[f] = checked
+	return checked
+}
+
+// funcWalker ... : 함수 하나의 문장을 순서대로 따라가며 identity 확인 없이 도달하는 ledger 쓰기, 함수 호출을 수집
+type funcWalker struct {
+	analyzer *ACAnalyzer
+	identity map[types.Object]bool // identity 확인 호출의 결과를 저장한 변수
+	hits     []ledgerWrite
+}
+
+// usesIdentity ... : node 에 identity 확인 호출 또는 그 결과를 저장한 변수가 있는지 여부
+func (walker *funcWalker) usesIdentity(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			found = found || walker.analyzer.isIdentityCheck(x)
+		case *ast.Ident:
+			if obj := walker.analyzer.info.Uses[x]; obj != nil && walker.identity[obj] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isGuard ... : identity 확인 결과로 분기하는 문장 (이후의 문장은 확인된 것으로 봄)
+func (walker *funcWalker) isGuard(stmt ast.Stmt) bool {
+	switch x := stmt.(type) {
+	case *ast.IfStmt:
+		return (x.Init != nil && walker.usesIdentity(x.Init)) || walker.usesIdentity(x.Cond)
+	case *ast.SwitchStmt:
+		return (x.Init != nil && walker.usesIdentity(x.Init)) || (x.Tag != nil && walker.usesIdentity(x.Tag))
+	}
+	return false
+}
+
+// walk ... : 문장 목록에서 identity 확인 결과로 분기하기 전까지 도달하는 ledger 쓰기와 함수 호출을 수집
+func (walker *funcWalker) walk(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		if walker.isGuard(stmt) {
+			return
+		}
+		if assign, ok := stmt.(*ast.AssignStmt); ok {
+			for _, rhs := range assign.Rhs {
+				if walker.usesIdentity(rhs) {
+					for _, lhs := range assign.Lhs {
+						ident, ok := lhs.(*ast.Ident)
+						if !ok {
+							continue
+						}
+						if obj := walker.analyzer.info.Defs[ident]; obj != nil {
+							walker.identity[obj] = true
+						} else if obj := walker.analyzer.info.Uses[ident]; obj != nil {
+							walker.identity[obj] = true
+						}
+					}
+				}
+			}
+		}
+		walker.scan(stmt)
+	}
+}
+
+// scan ... : 문장 하나의 호출을 수집 (중첩된 block 은 walk 로 따로 분석)
+func (walker *funcWalker) scan(stmt ast.Stmt) {
+	ast.Inspect(stmt, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			walker.walk(x.List)
+			return false
+		case *ast.CaseClause:
+			walker.walk(x.Body)
+			return false
+		case *ast.CommClause:
+			walker.walk(x.Body)
+			return false
+		case *ast.CallExpr:
+			if walker.analyzer.isWrite(x) {
+				walker.hits = append(walker.hits, ledgerWrite{call: x, funcName: icg.NodeString(walker.analyzer.fs, x.Fun)})
+			} else if f := calledFunc(x, walker.analyzer.info); f != nil {
+				if _, ok := walker.analyzer.decls[f]; ok {
+					walker.hits = append(walker.hits, ledgerWrite{call: x, funcName: icg.NodeString(walker.analyzer.fs, x.Fun), callee: f})
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (analyzer *ACAnalyzer) walk(decl *ast.FuncDecl) []ledgerWrite {
+	walker := &funcWalker{analyzer: analyzer, identity: make(map[types.Object]bool)}
+	walker.walk(decl.Body.List)
+	return walker.hits
+}
+
+// unguardedWrite ... : 함수 f 에서 identity 확인 없이 도달하는 ledger 쓰기 (다른 함수를 통하는 경우 포함)
+func (analyzer *ACAnalyzer) unguardedWrite(f *types.Func) *ledgerWrite {
+	if write, ok := analyzer.writes[f]; ok {
+		return write
+	}
+	analyzer.writes[f] = nil // 재귀 호출
+
+	for _, hit := range analyzer.walk(analyzer.decls[f]) {
+		if hit.callee == nil || analyzer.unguardedWrite(hit.callee) != nil {
+			write := hit
+			analyzer.writes[f] = &write
+			break
+		}
+	}
+	return analyzer.writes[f]
+}
+
+func (analyzer *ACAnalyzer) report(f *types.Func, decl *ast.FuncDecl, write *ledgerWrite, entry *ast.FuncDecl, call *ast.CallExpr) {
+	// 다른 함수를 통하는 경우 실제 ledger 쓰기 호출까지 따라감
+	last := write
+	for last.callee != nil {
+		last = analyzer.writes[last.callee]
+	}
+
+	var ccw CCW = MISSING_ACCESS_CONTROL
+	position := analyzer.fs.Position(decl.Name.Pos())
+	message := fmt.Sprintf("%s writes to the ledger (%s) without checking the client identity", f.Name(), last.funcName)
+	finding := newFinding(ccw, position.Filename, position.Line, position.Column, message)
+	finding.Function = f.Name()
+
+	writePos := analyzer.fs.Position(last.call.Pos())
+	finding.AddRelated(writePos.Filename, writePos.Line, "ledger is written by "+last.funcName)
+	if call != nil {
+		callPos := analyzer.fs.Position(call.Pos())
+		finding.AddRelated(callPos.Filename, callPos.Line, "called from "+entry.Name.Name)
+	}
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// Analysis ... : 패키지의 모든 파일에서 진입 함수 (Invoke, contract 의 트랜잭션 함수) 와 진입 함수가 identity 확인 없이 호출하는 함수를 분석
+func (analyzer *ACAnalyzer) Analysis(files []*ast.File) int {
+	decls, entries := transactionFuncs(files, analyzer.info)
+	analyzer.decls = decls
+
+	reported := make(map[*types.Func]bool)
+	for _, entry := range entries {
+		entryFunc, _ := analyzer.info.Defs[entry.Name].(*types.Func)
+		for _, hit := range analyzer.walk(entry) {
+			if hit.callee == nil {
+				if !reported[entryFunc] {
+					reported[entryFunc] = true
+					write := hit
+					analyzer.report(entryFunc, entry, &write, entry, nil)
+				}
+				continue
+			}
+			if reported[hit.callee] {
+				continue
+			}
+			if write := analyzer.unguardedWrite(hit.callee); write != nil {
+				reported[hit.callee] = true
+				analyzer.report(hit.callee, analyzer.decls[hit.callee], write, entry, hit.call)
+			}
+		}
+	}
+	return analyzer.analysisCount
+}
@@ -0,0 +1,208 @@
+package wah
+
+import (
+	"fmt"
+	"strconv"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// ALAnalyzer ...
+// GetStringArgs, GetArgs, GetFunctionAndParameters 가 반환한 인자 slice 를 길이 검사 없이 index 로 접근하는 코드 탐지 (UNCHECKED_ARGUMENT_LENGTH)
+// 실행 경로마다 len(args) 비교 분기로 보장되는 최소 길이를 기록하고, 상수 index 가 그 길이 이상이면 보고
+type ALAnalyzer struct {
+	analysisFile  string
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	sources       map[int]*icg.ControlOpcode // 인자 slice 를 저장하는 definition 라인, 인자를 읽는 call
+	minLength     map[int]int                // 인자 slice 의 definition 라인, 현재 경로에서 보장되는 최소 길이
+	reported      map[string]bool
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+func (analyzer *ALAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo) {
+	analyzer.analysisFile = analysisFile
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.sources = nil
+	analyzer.minLength = make(map[int]int)
+	analyzer.reported = make(map[string]bool)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// Copy ... : 분기 이전의 최소 길이로 되돌리기 위한 복사
+func (analyzer *ALAnalyzer) Copy() *ALAnalyzer {
+	newAnalyzer := new(ALAnalyzer)
+	*newAnalyzer = *analyzer
+	newAnalyzer.minLength = make(map[int]int)
+	for def, length := range analyzer.minLength {
+		newAnalyzer.minLength[def] = length
+	}
+
+	return newAnalyzer
+}
+
+// findSources ... : 함수 안에서 인자 slice 를 저장하는 definition 목록 (GetFunctionAndParameters 는 두 번째 반환 값)
+func (analyzer *ALAnalyzer) findSources() map[int]*icg.ControlOpcode {
+	sources := make(map[int]*icg.ControlOpcode)
+	for _, code := range analyzer.codeList {
+		call, ok := code.(*icg.ControlOpcode)
+		if !ok || call.Opcode() != icg.Call || !matchFunc(fmt.Sprint(call.Params().Front().Value), analyzer.config.ArgumentSources) {
+			continue
+		}
+		if results := CallResults(call, analyzer.codeList); len(results) > 0 {
+			sources[results[len(results)-1].GetLine()] = call
+		}
+	}
+	return sources
+}
+
+func (analyzer *ALAnalyzer) report(line int, index int, source *icg.ControlOpcode) {
+	key := fmt.Sprintf("%d:%d", line, index)
+	if analyzer.reported[key] {
+		return
+	}
+	analyzer.reported[key] = true
+
+	var ccw CCW = UNCHECKED_ARGUMENT_LENGTH
+	sourceName := fmt.Sprint(source.Params().Front().Value)
+	message := fmt.Sprintf("argument %d returned by %s is accessed without checking that the argument count is at least %d", index, sourceName, index+1)
+	finding := newFinding(ccw, analyzer.analysisFile, line, 0, message)
+	finding.AddRelated(analyzer.analysisFile, source.GetSourceLine(), "arguments are read by "+sourceName)
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// lengthCondition ... : 분기 조건이 len(args) 와 상수의 비교인 경우 인자 slice 의 definition, 비교 연산자 (len 이 왼쪽), 상수
+//
+//	ex ) len(args) < 2 : ldp, lod args, call len, ldc 2, lt
+//	     2 > len(args) : ldc 2, ldp, lod args, call len, gt (lt 로 변환)
+func (analyzer *ALAnalyzer) lengthCondition(block *cfg.BranchBlock) (int, icg.Opcode, int, bool) {
+	index := findSILIndex(analyzer.codeList, block.CodeList()[0].GetLine())
+	if index < 5 {
+		return 0, 0, 0, false
+	}
+	cmp := analyzer.codeList[index-1].Opcode()
+	if cmp < icg.Eq || icg.Lt < cmp {
+		return 0, 0, 0, false
+	}
+
+	lenIndex, ldcIndex := index-3, index-2
+	if analyzer.codeList[index-2].Opcode() == icg.Call {
+		lenIndex, ldcIndex = index-2, index-5
+		switch cmp {
+		case icg.Lt:
+			cmp = icg.Gt
+		case icg.Le:
+			cmp = icg.Ge
+		case icg.Gt:
+			cmp = icg.Lt
+		case icg.Ge:
+			cmp = icg.Le
+		}
+	}
+
+	call, ok := analyzer.codeList[lenIndex].(*icg.ControlOpcode)
+	if !ok || call.Opcode() != icg.Call || fmt.Sprint(call.Params().Front().Value) != "len" {
+		return 0, 0, 0, false
+	}
+	ldc, ok := analyzer.codeList[ldcIndex].(*icg.StackOpcode)
+	if !ok || ldc.Opcode() != icg.Ldc {
+		return 0, 0, 0, false
+	}
+	n, err := strconv.Atoi(fmt.Sprint(ldc.Params().Front().Value))
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	lod, ok := analyzer.codeList[lenIndex-1].(*icg.StackOpcode)
+	if !ok || lod.Opcode() != icg.Lod {
+		return 0, 0, 0, false
+	}
+	def, ok := reachingDefinition(fmt.Sprint(lod.Params().Front().Next().Value), lod, analyzer.chain, analyzer.codeList)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return def, cmp, n, true
+}
+
+// Branch ... : 분기 block 의 한쪽 경로를 분석하기 전에 호출
+// len(args) 비교 조건이 이 경로에서 참 (또는 거짓) 이면 보장되는 최소 길이를 기록
+func (analyzer *ALAnalyzer) Branch(block *cfg.BranchBlock, isUjp bool) {
+	if block.BranchType() == cfg.UnconditionBranch {
+		return
+	}
+	def, cmp, n, ok := analyzer.lengthCondition(block)
+	if !ok {
+		return
+	}
+
+	// fjp 는 조건이 참이면 다음 block (UjpBlock), tjp 는 조건이 거짓이면 다음 block 으로 진행
+	isTrue := isUjp == (block.BranchType() == cfg.FalseBranch)
+	length := 0
+	switch {
+	case isTrue && cmp == icg.Gt, !isTrue && cmp == icg.Le:
+		length = n + 1
+	case isTrue && (cmp == icg.Ge || cmp == icg.Eq), !isTrue && (cmp == icg.Lt || cmp == icg.Ne):
+		length = n
+	}
+	if length > analyzer.minLength[def] {
+		analyzer.minLength[def] = length
+	}
+}
+
+// argumentIndex ... : 인자 slice 의 상수 index 접근 (lda args, ldc index*4, cvi, cvui, add)
+func (analyzer *ALAnalyzer) argumentIndex(lda *icg.StackOpcode) (int, int, bool) {
+	index := findSILIndex(analyzer.codeList, lda.GetLine())
+	if index == -1 || index+4 >= len(analyzer.codeList) {
+		return 0, 0, false
+	}
+	ldc, ok := analyzer.codeList[index+1].(*icg.StackOpcode)
+	if !ok || ldc.Opcode() != icg.Ldc || analyzer.codeList[index+2].Opcode() != icg.Cvi || analyzer.codeList[index+3].Opcode() != icg.Cvui {
+		return 0, 0, false
+	}
+	// SIL 의 index 는 원소 크기 (4) 를 곱한 값
+	n, err := strconv.Atoi(fmt.Sprint(ldc.Params().Front().Value))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	def, ok := reachingDefinition(fmt.Sprint(lda.Params().Front().Next().Value), lda, analyzer.chain, analyzer.codeList)
+	if !ok {
+		return 0, 0, false
+	}
+	return def, n / 4, true
+}
+
+func (analyzer *ALAnalyzer) ALAnalysis(block cfg.CFGBlock) int {
+	switch b := block.(type) {
+	case *cfg.BasicBlock:
+		for _, sil := range b.CodeList() {
+			lda, ok := sil.(*icg.StackOpcode)
+			if !ok || lda.Opcode() != icg.Lda || lda.Type() == icg.Sp {
+				continue
+			}
+
+			if analyzer.sources == nil {
+				analyzer.sources = analyzer.findSources()
+			}
+			if len(analyzer.sources) == 0 {
+				return analyzer.analysisCount
+			}
+
+			def, index, ok := analyzer.argumentIndex(lda)
+			if !ok || analyzer.sources[def] == nil {
+				continue
+			}
+			if index >= analyzer.minLength[def] {
+				analyzer.report(lda.GetSourceLine(), index, analyzer.sources[def])
+			}
+		}
+	}
+	return analyzer.analysisCount
+}
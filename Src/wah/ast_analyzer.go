@@ -5,7 +5,6 @@ import (
    "go/ast"
    "go/token"
    "go/types"
-   "strings"
 
    "WAH_prototype_go-master/Src/icg"
 )
@@ -16,6 +15,10 @@ type ASTAnalyzer struct {
    fs           *token.FileSet
    ueCompleteFuncList []string
    analysisCount int
+   file          *ast.File
+   files         []*ast.File // 패키지의 모든 파일 (helper 함수 검색, nil 이면 file 만 사용)
+   findings      []Finding
+   config        *Config
 }
 
 func (analyzer *ASTAnalyzer) Init(analysisFile string, fset *token.FileSet) {
@@ -23,6 +26,44 @@ func (analyzer *ASTAnalyzer) Init(analysisFile string, fset *token.FileSet) {
    analyzer.FuncRetTable = make(map[string]int)
    analyzer.fs = fset
    analyzer.analysisCount = 0
+   analyzer.config = DefaultConfig()
+}
+
+//SetConfig ... : 프로젝트 설정의 함수 목록을 사용
+func (analyzer *ASTAnalyzer) SetConfig(config *Config) {
+   analyzer.config = config
+}
+
+// enclosingFunc ... : pos를 포함하는 함수 이름 (함수 밖인 경우 "")
+func (analyzer *ASTAnalyzer) enclosingFunc(pos token.Pos) string {
+   if analyzer.file == nil {
+      return ""
+   }
+   for _, decl := range analyzer.file.Decls {
+      if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+         if funcDecl.Pos() <= pos && pos < funcDecl.End() {
+            return funcDecl.Name.Name
+         }
+      }
+   }
+   return ""
+}
+
+func (analyzer *ASTAnalyzer) report(ccw CCW, node ast.Node, position token.Position, message string) {
+   finding := newFinding(ccw, analyzer.analysisFile, position.Line, position.Column, message)
+   finding.Function = analyzer.enclosingFunc(node.Pos())
+   analyzer.findings = append(analyzer.findings, finding)
+   analyzer.analysisCount ++
+}
+
+//SetFiles ... : 패키지 단위로 분석하는 경우 다른 파일에 선언된 함수도 검색
+func (analyzer *ASTAnalyzer) SetFiles(files []*ast.File) {
+   analyzer.files = files
+}
+
+//Findings ...
+func (analyzer *ASTAnalyzer) Findings() []Finding {
+   return analyzer.findings
 }
 
 //MapStructureIteration...
@@ -38,15 +79,7 @@ func (analyzer *ASTAnalyzer) MSIAnalysis(node ast.Node, info *types.Info) {
       if tv, ok := info.Types[rangeFor.X]; ok {
          _, isMap := tv.Type.(*types.Map)
          if isMap {
-            linenum := position.Line
-            if isFirstDetect {
-               fmt.Printf("chaincode weakness detected:\n")
-               isFirstDetect = false
-            }
-            fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-            fmt.Printf("\t not use a map type \"%s\" in loop range\n", rangeFor.X)
-            fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-            analyzer.analysisCount ++
+            analyzer.report(ccw, node, position, fmt.Sprintf("not use a map type \"%s\" in loop range", rangeFor.X))
          }
       }
    }
@@ -60,15 +93,7 @@ func (analyzer *ASTAnalyzer) UsedGoroutineAnalysis(node ast.Node, info *types.In
    }
    if goStmt, ok := node.(*ast.GoStmt); ok {
       if tv, ok := info.Types[goStmt.Call.Fun]; ok {
-         linenum := position.Line
-         if isFirstDetect {
-            fmt.Printf("chaincode weakness detected:\n")
-            isFirstDetect = false
-         }
-         fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-         fmt.Printf("\t not use go routine \"go %v\"\n", tv.Type.Underlying())
-         fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-         analyzer.analysisCount ++
+         analyzer.report(ccw, node, position, fmt.Sprintf("not use go routine \"go %v\"", tv.Type.Underlying()))
       }
    }
 }
@@ -103,15 +128,7 @@ func (analyzer *ASTAnalyzer) UnhandledErrorsAnalysis(node ast.Node, info *types.
             if errLocation != -1 {
                if ident, ok := assign.Lhs[errLocation].(*ast.Ident); ok {
                   if ident.Name == "_" {
-                     linenum := position.Line
-                     if isFirstDetect {
-                        fmt.Printf("chaincode weakness detected:\n")
-                        isFirstDetect = false
-                     }
-                     fmt.Printf( "\t CCW-%03d : %s\n", ccw, ccw.String())
-
-                     fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-                     analyzer.analysisCount ++
+                     analyzer.report(ccw, node, position, fmt.Sprintf("the error returned by \"%s\" is assigned to \"_\"", funcName))
                      //fmt.Printf("\t   The %d return type of rhs( %s ) is error, but is not assigned to the %d lhs ( _ ).\n\n", errLocation, funcName, errLocation)
                      errLocation = -1
                   }
@@ -140,16 +157,8 @@ func (analyzer *ASTAnalyzer) PhantomReadAnalysis(node ast.Node, info *types.Info
          if call, ok := rhs.(*ast.CallExpr); ok {
             funcName = icg.NodeString(analyzer.fs, call.Fun)
 
-            if strings.Contains(funcName, "GetHistoryForKey") || strings.Contains(funcName, "GetQueryResult") {
-               linenum := position.Line
-               if isFirstDetect {
-                  fmt.Printf("chaincode weakness detected:\n")
-                  isFirstDetect = false
-               }
-               fmt.Printf( "\t CCW-%03d : %s\n", ccw, ccw.String())
-
-               fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-               analyzer.analysisCount ++
+            if matchFunc(funcName, analyzer.config.PhantomReadQueries) {
+               analyzer.report(ccw, node, position, fmt.Sprintf("\"%s\" is not re-executed at validation time", funcName))
             }
          }
       }
@@ -169,81 +178,63 @@ func (analyzer *ASTAnalyzer) RQRAnalysis(node ast.Node, info *types.Info) {
          if call, ok := rhs.(*ast.CallExpr); ok {
             funcName = icg.NodeString(analyzer.fs, call.Fun)
 
-            if strings.Contains(funcName, "GetHistoryForKey") || strings.Contains(funcName, "GetQueryResult")  || strings.Contains(funcName, "GetPrivateDataQueryResult"){
-               linenum := position.Line
-               if isFirstDetect {
-                  fmt.Printf("chaincode weakness detected:\n")
-                  isFirstDetect = false
-               }
-               fmt.Printf( "\t CCW-%03d : %s\n", ccw, ccw.String())
-
-               fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-               analyzer.analysisCount ++
+            if matchFunc(funcName, analyzer.config.RangeQueries) {
+               analyzer.report(ccw, node, position, fmt.Sprintf("range query \"%s\"", funcName))
             }
          }
       }
    }
 }
 
-func (analyzer *ASTAnalyzer) SystemCommandAnalysis(node ast.Node, info *types.Info) {
-   var ccw CCW = SYSTEM_COMMANDS
-   var position token.Position
-
-	 if node != nil {
-			position = analyzer.fs.Position(node.Pos())
-	 }
-
-   switch x := node.(type) {
-    case *ast.BasicLit:
-      if strings.Contains(x.Value, "os/exec"){
-         linenum := position.Line
-         if isFirstDetect {
-            fmt.Printf("chaincode weakness detected:\n")
-            isFirstDetect = false
-         }
-         fmt.Printf( "\t CCW-%03d : %s\n", ccw, ccw.String())
-
-         fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-         analyzer.analysisCount ++
-      }
-    }
-}
-
-func (analyzer *ASTAnalyzer) SystemTimestampAnalysis(node ast.Node, info *types.Info) {
-   var ccw CCW = SYSTEM_TIMESTAMP
-   var position token.Position
+// ExternalAccessAnalysis ... : 실행 결과가 peer 마다 달라지는 외부 접근 (system command, network, file, 환경 변수) 호출 탐지
+// system time 은 ledger, 응답, 분기 조건에 사용되는 경우만 TSAnalyzer 에서 탐지
+// import 이름 (alias) 과 관계없이 타입 정보 (info.Uses) 로 호출되는 함수를 찾아 호출 위치에 보고
+func (analyzer *ASTAnalyzer) ExternalAccessAnalysis(node ast.Node, info *types.Info) {
+   call, ok := node.(*ast.CallExpr)
+   if !ok {
+      return
+   }
+   f := calledFunc(call, info)
+   if f == nil {
+      return
+   }
 
-	 if node != nil {
-			position = analyzer.fs.Position(node.Pos())
-	 }
-
-   switch x := node.(type) {
-    case *ast.BasicLit:
-      if strings.Contains(x.Value, "time"){
-         linenum := position.Line
-         if isFirstDetect {
-            fmt.Printf("chaincode weakness detected:\n")
-            isFirstDetect = false
-         }
-         fmt.Printf( "\t CCW-%03d : %s\n", ccw, ccw.String())
+   var ccw CCW
+   var message string
+   switch {
+   case matchCall(f, analyzer.config.SystemCommands):
+      ccw, message = SYSTEM_COMMANDS, "executes a system command"
+   case matchCall(f, analyzer.config.NetworkAccess):
+      ccw, message = NETWORK_ACCESS, "accesses the network"
+   case matchCall(f, analyzer.config.FileAccess):
+      ccw, message = FILE_ACCESS, "accesses the file system"
+   case matchCall(f, analyzer.config.EnvironmentReads):
+      ccw, message = ENVIRONMENT_ACCESS, "reads the environment of the peer"
+   default:
+      return
+   }
 
-         fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
-         analyzer.analysisCount ++
-      }
-    }
+   position := analyzer.fs.Position(call.Pos())
+   analyzer.report(ccw, node, position, fmt.Sprintf("\"%s\" (%s) %s", icg.NodeString(analyzer.fs, call.Fun), f.FullName(), message))
 }
 
 //Analyze ...
 func (analyzer *ASTAnalyzer) Analysis(f *ast.File, info *types.Info) int{
+   analyzer.file = f
+   decls := analyzer.funcDecls(info)
+   params := analyzer.keyInputParams(info, decls)
 
    ast.Inspect(f, func(node ast.Node) bool {
+      if funcDecl, ok := node.(*ast.FuncDecl); ok {
+         analyzer.MapSerializationAnalysis(funcDecl, info, decls)
+         analyzer.KeyInputAnalysis(funcDecl, info, params)
+      }
       analyzer.MSIAnalysis(node, info)
       analyzer.UsedGoroutineAnalysis(node, info)
       analyzer.UnhandledErrorsAnalysis(node, info)
       analyzer.PhantomReadAnalysis(node,info)
       analyzer.RQRAnalysis(node,info)
-			analyzer.SystemCommandAnalysis(node, info)
-			analyzer.SystemTimestampAnalysis(node, info)
+      analyzer.ExternalAccessAnalysis(node, info)
       return true
    })
 
@@ -0,0 +1,184 @@
+package wah
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const baselineVersion = 1
+
+// BaselineEntry ...
+// baseline 파일에 기록된 보안약점 하나
+// fingerprint 는 라인 번호 대신 CCW 식별자, 파일, 함수, 정규화된 소스 코드로 계산하므로 코드가 이동해도 유지됨
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Function    string `json:"function,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+}
+
+// Baseline ...
+// 이미 알려진 (수정을 미룬) 보안약점 목록
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// fingerprinter ...
+// 탐지 결과의 소스 코드를 읽어 fingerprint 를 계산 (파일 내용은 한 번만 읽음)
+type fingerprinter struct {
+	sources map[string][]string
+}
+
+func (fp *fingerprinter) Init() {
+	fp.sources = make(map[string][]string)
+}
+
+// lines ... : file 의 소스 코드 (라인 단위, 읽을 수 없는 경우 nil)
+func (fp *fingerprinter) lines(file string) []string {
+	lines, ok := fp.sources[file]
+	if !ok {
+		if data, err := ioutil.ReadFile(file); err == nil {
+			lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		}
+		fp.sources[file] = lines
+	}
+	return lines
+}
+
+// snippet ... : file 의 line 번째 라인을 공백을 정규화하여 반환 (읽을 수 없는 경우 "")
+func (fp *fingerprinter) snippet(file string, line int) string {
+	lines := fp.lines(file)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.Join(strings.Fields(lines[line-1]), " ")
+}
+
+// baselinePath ... : file 을 module root (go.mod 가 있는 가장 가까운 상위 디렉터리) 기준 상대 경로로 변환
+// module root 를 찾을 수 없는 경우 작업 디렉터리 기준 상대 경로를 사용하므로 분석 대상을 지정한 방법 (./x, /abs/x) 과 관계없이 같은 경로가 됨
+func baselinePath(file string) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(file))
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		root = filepath.Dir(abs)
+	}
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			root = dir
+			break
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+
+	if rel, err := filepath.Rel(root, abs); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(abs)
+}
+
+func (fp *fingerprinter) fingerprint(f *Finding) {
+	file := baselinePath(f.File)
+	f.Snippet = fp.snippet(f.File, f.Line)
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{f.ID, file, f.Function, f.Snippet}, "\x00")))
+	f.Fingerprint = hex.EncodeToString(hash[:16])
+}
+
+// fingerprintFindings ... : 모든 탐지 결과의 fingerprint 와 소스 코드를 기록
+func fingerprintFindings(findings []Finding) {
+	fp := new(fingerprinter)
+	fp.Init()
+	for i := range findings {
+		fp.fingerprint(&findings[i])
+	}
+}
+
+// NewBaseline ... : 탐지 결과로 baseline 생성
+func NewBaseline(findings []Finding) Baseline {
+	baseline := Baseline{Version: baselineVersion, Entries: []BaselineEntry{}}
+	for _, f := range findings {
+		baseline.Entries = append(baseline.Entries, BaselineEntry{
+			Fingerprint: f.Fingerprint,
+			ID:          f.ID,
+			Name:        f.Name,
+			File:        baselinePath(f.File),
+			Line:        f.Line,
+			Function:    f.Function,
+			Snippet:     f.Snippet,
+		})
+	}
+	return baseline
+}
+
+// ReadBaseline ... : baseline 파일 읽기
+func ReadBaseline(path string) (Baseline, error) {
+	var baseline Baseline
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return baseline, err
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return baseline, fmt.Errorf("could not read baseline %s: %v", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return baseline, fmt.Errorf("could not read baseline %s: unsupported version %d", path, baseline.Version)
+	}
+	return baseline, nil
+}
+
+// WriteBaseline ... : baseline 파일 생성
+func WriteBaseline(path string, baseline Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ApplyBaseline ... : baseline 에 기록된 보안약점을 보고 대상에서 제외
+// 같은 fingerprint 의 보안약점이 여러 개인 경우 baseline 에 기록된 개수만큼만 제외하며
+// 더 이상 탐지되지 않는 baseline 항목은 수정된 것으로 기록
+func (r *Report) ApplyBaseline(baseline Baseline) {
+	remain := make(map[string][]BaselineEntry)
+	var order []string
+	for _, entry := range baseline.Entries {
+		if _, ok := remain[entry.Fingerprint]; !ok {
+			order = append(order, entry.Fingerprint)
+		}
+		remain[entry.Fingerprint] = append(remain[entry.Fingerprint], entry)
+	}
+
+	r.hasBaseline = true
+
+	var findings []Finding
+	for _, f := range r.Findings {
+		if entries := remain[f.Fingerprint]; len(entries) > 0 {
+			remain[f.Fingerprint] = entries[1:]
+			r.Baselined = append(r.Baselined, f)
+			continue
+		}
+		findings = append(findings, f)
+	}
+	r.Findings = findings
+
+	for _, fingerprint := range order {
+		r.Fixed = append(r.Fixed, remain[fingerprint]...)
+	}
+}
@@ -1,7 +1,11 @@
 package wah
 
+import (
+	"fmt"
+	"strings"
+)
+
 var isDebug bool = false
-var isFirstDetect = true
 type CCW int
 
 const (
@@ -16,10 +20,104 @@ const (
 	RANGE_QUERY_RISK
 	SYSTEM_COMMANDS
 	SYSTEM_TIMESTAMP
+	CROSS_CHAINCODE_INVOCATION
+	PRIVATE_DATA_LEAK
+	DUPLICATE_KEY_WRITE
+	UNCLOSED_ITERATOR
+	UNCHECKED_ARGUMENT_LENGTH
+	MAP_SERIALIZATION
+	MISSING_ACCESS_CONTROL
+	NETWORK_ACCESS
+	FILE_ACCESS
+	ENVIRONMENT_ACCESS
+	RUNTIME_PANIC
+	UNCHECKED_ARITHMETIC
+	READ_AFTER_DELETE
+	UNVALIDATED_KEY_INPUT
+	GLOBAL_STATE_MUTATION
 )
 
 func (c CCW) String() string {
 	return [...]string{"MAP_STRUCTURE_ITER", "RANDOM_NUMBER_GENERATION",
 		"GF_DECLARATION", "UNCHECKED_INPUT_ARGUMENTS", "UNHANDLED_ERROR", "USED_GOROUTINE",
-		"PHANTOM_READS", "READ_YOUR_WRITE", "RANGE_QUERY_RISK","SYSTEM_COMMANDS","SYSTEM_TIMESTAMP"}[c-1]
+		"PHANTOM_READS", "READ_YOUR_WRITE", "RANGE_QUERY_RISK","SYSTEM_COMMANDS","SYSTEM_TIMESTAMP",
+		"CROSS_CHAINCODE_INVOCATION", "PRIVATE_DATA_LEAK", "DUPLICATE_KEY_WRITE", "UNCLOSED_ITERATOR",
+		"UNCHECKED_ARGUMENT_LENGTH", "MAP_SERIALIZATION", "MISSING_ACCESS_CONTROL",
+		"NETWORK_ACCESS", "FILE_ACCESS", "ENVIRONMENT_ACCESS", "RUNTIME_PANIC",
+		"UNCHECKED_ARITHMETIC", "READ_AFTER_DELETE",
+		"UNVALIDATED_KEY_INPUT", "GLOBAL_STATE_MUTATION"}[c-1]
+}
+
+// Description ... : 보안약점에 대한 설명 (report 의 help text 로 사용)
+func (c CCW) Description() string {
+	return [...]string{
+		"Iterating over a map yields keys in a random order, so endorsing peers may compute different results.",
+		"Random numbers differ between endorsing peers and break the endorsement policy.",
+		"Global variables and chaincode receiver fields are local to each peer and must not be used as ledger keys or values.",
+		"Input arguments are used without checking their number or content.",
+		"An error returned by a function is ignored or not handled.",
+		"Goroutines make the execution order non-deterministic between endorsing peers.",
+		"GetHistoryForKey and GetQueryResult are not re-executed during validation, so phantom reads are not detected.",
+		"GetState does not return a value written by PutState in the same transaction.",
+		"Range and rich queries are not re-executed during validation, so the result may be stale.",
+		"Executing system commands makes the result depend on the environment of each peer.",
+		"The system time differs between endorsing peers, so ledger writes, events, responses and branches that depend on it diverge; use the transaction timestamp (GetTxTimestamp) instead.",
+		"InvokeChaincode on another channel discards the writes and does not validate the reads of the called chaincode.",
+		"Private data written to the public state, an event or the response is visible to every peer on the channel.",
+		"Only the last write to a key in a transaction is committed, so an earlier PutState or DelState on the same key is lost.",
+		"A state query iterator that is not closed on every path leaks resources on the peer.",
+		"An input argument is accessed by index without checking the number of arguments, so short input panics the chaincode.",
+		"A map serialized without ordering its keys, or built by iterating over a map, gives a different byte sequence on each endorsing peer.",
+		"A transaction writes to the ledger without checking the identity of the client that submitted it.",
+		"Network requests made by chaincode depend on external services, so endorsing peers may receive different responses.",
+		"Files read or written by chaincode are local to each peer, so endorsing peers may compute different results.",
+		"Environment variables and host information differ between peers, so endorsing peers may compute different results.",
+		"A panic, failed type assertion or write to a nil map aborts the transaction with an opaque error, and log.Fatal or os.Exit stops the chaincode process.",
+		"Integer arithmetic on ledger values or input arguments can overflow, become negative or divide by zero before the result is written to the ledger.",
+		"DelState is not applied until the transaction is committed, so a later GetState or range query in the same transaction still returns the deleted key.",
+		"Input arguments or transient data used as a ledger key without validation can contain the composite key separator or another entity's prefix and overwrite other entities.",
+		"Package-level variables and chaincode receiver fields written by a transaction stay only in the memory of the peer that executed it, so other peers, or the same peer after a restart, see different values.",
+	}[c-1]
+}
+
+// DefaultSeverity ... : 보안약점의 기본 심각도
+func (c CCW) DefaultSeverity() Severity {
+	return [...]Severity{High, High, Medium, Medium, Medium, High, Medium, High, Medium, High, High, Medium, High, High, Medium, Medium, High, Medium, High, High, High, Medium, High, High, High, Medium}[c-1]
+}
+
+// CCWList ... : 정의된 모든 보안약점 목록
+func CCWList() []CCW {
+	var res []CCW
+	for c := CCW(MAP_STRUCTURE_ITER); c <= GLOBAL_STATE_MUTATION; c++ {
+		res = append(res, c)
+	}
+	return res
+}
+
+//Severity ...
+type Severity int
+
+const (
+	Low Severity = iota + 1
+	Medium
+	High
+)
+
+func (s Severity) String() string {
+	return [...]string{"low", "medium", "high"}[s-1]
+}
+
+// ParseSeverity ... : "low", "medium", "high" 를 Severity 로 변환
+func ParseSeverity(s string) (Severity, error) {
+	for severity := Low; severity <= High; severity++ {
+		if strings.EqualFold(s, severity.String()) {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q (expected low, medium or high)", s)
+}
+
+// MarshalText ... : JSON 출력 시 심각도를 문자열로 출력
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
 }
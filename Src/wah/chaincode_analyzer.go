@@ -1,9 +1,12 @@
 package wah
 
 import (
+	"errors"
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"os/exec"
 
 	"WAH_prototype_go-master/Src/analysisGraph/cfg"
 	"WAH_prototype_go-master/Src/analysisGraph/vfg"
@@ -18,20 +21,38 @@ type ChainCodeAnalyzer struct {
 	ueAnalyzer  *UEAnalyzer
 	rywAnalyzer *RYWAnalyzer
 	rngAnalyzer *RNGAnalyzer
+	icAnalyzer  *ICAnalyzer
+	pdAnalyzer  *PDAnalyzer
+	wwAnalyzer  *WWAnalyzer
+	itAnalyzer  *ITAnalyzer
+	alAnalyzer  *ALAnalyzer
+	iaAnalyzer  *IAAnalyzer
+	tsAnalyzer  *TSAnalyzer
 
 	codeList            []icg.CodeInfo
+	analysisFile        string
+	funcName            string
+	failed              map[string]bool
+	errs                []AnalysisError
 	isFirstAnalysis     bool
 	isFirstGraphAnalsis bool
 	totalCount          int
 }
 
-func (cca *ChainCodeAnalyzer) Init(fs *token.FileSet, analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo,litTable *symbolTable.LiteralTable) {
+func (cca *ChainCodeAnalyzer) Init(fs *token.FileSet, analysisFile string, funcName string, chain vfg.DUChain, codeList []icg.CodeInfo,litTable *symbolTable.LiteralTable) {
 	cca.astAnalyzer = new(ASTAnalyzer)
 	cca.gfAnalyzer = new(GFDeclAnalyzer)
 	cca.uiaAnalyzer = new(UIAAnalyzer)
 	cca.ueAnalyzer = new(UEAnalyzer)
 	cca.rywAnalyzer = new(RYWAnalyzer)
 	cca.rngAnalyzer = new(RNGAnalyzer)
+	cca.icAnalyzer = new(ICAnalyzer)
+	cca.pdAnalyzer = new(PDAnalyzer)
+	cca.wwAnalyzer = new(WWAnalyzer)
+	cca.itAnalyzer = new(ITAnalyzer)
+	cca.alAnalyzer = new(ALAnalyzer)
+	cca.iaAnalyzer = new(IAAnalyzer)
+	cca.tsAnalyzer = new(TSAnalyzer)
 
 	cca.astAnalyzer.Init(analysisFile, fs)
 	cca.gfAnalyzer.Init(analysisFile, chain, codeList)
@@ -39,19 +60,145 @@ func (cca *ChainCodeAnalyzer) Init(fs *token.FileSet, analysisFile string, chain
 	cca.ueAnalyzer.Init(analysisFile, chain, codeList)
 	cca.rywAnalyzer.Init(analysisFile, chain, codeList, litTable)
 	cca.rngAnalyzer.Init(analysisFile)
+	cca.icAnalyzer.Init(analysisFile, chain, codeList, litTable)
+	cca.pdAnalyzer.Init(analysisFile, chain, codeList)
+	cca.wwAnalyzer.Init(analysisFile, chain, codeList, litTable)
+	cca.itAnalyzer.Init(analysisFile, fs, chain, codeList)
+	cca.alAnalyzer.Init(analysisFile, chain, codeList)
+	cca.iaAnalyzer.Init(analysisFile, fs, chain, codeList)
+	cca.tsAnalyzer.Init(analysisFile, fs, funcName, chain, codeList)
 
 	cca.codeList = codeList
+	cca.analysisFile = analysisFile
+	cca.funcName = funcName
+	cca.failed = make(map[string]bool)
+	cca.errs = nil
 	cca.isFirstAnalysis = true
 	cca.isFirstGraphAnalsis = true
 	cca.totalCount = 0
 }
+// SetErrTable ... : AST 분석을 패키지 단위로 먼저 수행한 경우, 그 결과 (error 반환 위치) 만 전달하고 AST 분석은 생략
+func (cca *ChainCodeAnalyzer) SetErrTable(errTable map[string]int) {
+	cca.ueAnalyzer.SetErrTable(errTable)
+	cca.isFirstAnalysis = false
+}
+// SetConfig ... : 모든 분석기가 프로젝트 설정의 함수 목록을 사용
+func (cca *ChainCodeAnalyzer) SetConfig(config *Config) {
+	cca.astAnalyzer.SetConfig(config)
+	cca.gfAnalyzer.config = config
+	cca.ueAnalyzer.config = config
+	cca.rywAnalyzer.config = config
+	cca.icAnalyzer.config = config
+	cca.pdAnalyzer.config = config
+	cca.wwAnalyzer.config = config
+	cca.itAnalyzer.config = config
+	cca.alAnalyzer.config = config
+	cca.iaAnalyzer.config = config
+	cca.tsAnalyzer.config = config
+}
 func (cca *ChainCodeAnalyzer) TotalCount() int {
-	res := cca.astAnalyzer.analysisCount + cca.gfAnalyzer.analysisCount + cca.uiaAnalyzer.analysisCount + cca.ueAnalyzer.analysisCount + cca.rywAnalyzer.analysisCount + cca.rngAnalyzer.analysisCount
+	res := cca.astAnalyzer.analysisCount + cca.gfAnalyzer.analysisCount + cca.uiaAnalyzer.analysisCount + cca.ueAnalyzer.analysisCount + cca.rywAnalyzer.analysisCount + cca.rngAnalyzer.analysisCount + cca.icAnalyzer.analysisCount + cca.pdAnalyzer.analysisCount + cca.wwAnalyzer.analysisCount + cca.itAnalyzer.analysisCount + cca.alAnalyzer.analysisCount + cca.iaAnalyzer.analysisCount + cca.tsAnalyzer.analysisCount
+	return res
+}
+
+// 분석기 이름 (분석 오류에 기록되며 panic 이 발생한 분석기의 결과만 버림)
+const (
+	astDetector = "AST"
+	gfDetector  = "GF_DECLARATION"
+	uiaDetector = "UNCHECKED_INPUT_ARGUMENTS"
+	ueDetector  = "UNHANDLED_ERROR"
+	rywDetector = "READ_YOUR_WRITE/READ_AFTER_DELETE"
+	rngDetector = "RANDOM_NUMBER_GENERATION"
+	icDetector  = "CROSS_CHAINCODE_INVOCATION"
+	pdDetector  = "PRIVATE_DATA_LEAK"
+	wwDetector  = "DUPLICATE_KEY_WRITE"
+	itDetector  = "UNCLOSED_ITERATOR"
+	alDetector  = "UNCHECKED_ARGUMENT_LENGTH"
+	iaDetector  = "UNCHECKED_ARITHMETIC"
+	tsDetector  = "SYSTEM_TIMESTAMP"
+)
+
+// analyze ... : 분석기 하나를 실행하고 panic 이 발생하면 분석 오류로 기록
+// panic 이 발생한 분석기는 상태를 신뢰할 수 없으므로 이후 block 은 분석하지 않고 탐지 결과도 버림 (다른 분석기의 결과는 유지)
+func (cca *ChainCodeAnalyzer) analyze(detector string, analyze func()) {
+	if cca.failed[detector] {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			cca.failed[detector] = true
+			cca.errs = append(cca.errs, AnalysisError{File: cca.analysisFile, Function: cca.funcName, Message: fmt.Sprintf("%s was not checked: %v", detector, r)})
+		}
+	}()
+	analyze()
+}
+
+// Errors ... : 분석 중 panic 이 발생하여 결과를 버린 분석기와 z3 를 실행할 수 없어 검사를 생략한 분석기의 분석 오류
+// z3 가 설치되지 않은 경우는 함수마다 같으므로 함수 이름을 기록하지 않음
+func (cca *ChainCodeAnalyzer) Errors() []AnalysisError {
+	res := append([]AnalysisError(nil), cca.errs...)
+	for _, s := range []struct {
+		detector string
+		err      error
+	}{
+		{rywDetector, cca.rywAnalyzer.solverErr},
+		{wwDetector, cca.wwAnalyzer.solverErr},
+		{iaDetector, cca.iaAnalyzer.solverErr},
+	} {
+		if s.err == nil || cca.failed[s.detector] {
+			continue
+		}
+		e := AnalysisError{File: cca.analysisFile, Function: cca.funcName, Message: fmt.Sprintf("%s was not checked: could not run z3: %v", s.detector, s.err)}
+		if errors.Is(s.err, exec.ErrNotFound) {
+			e.Function = ""
+		}
+		res = append(res, e)
+	}
 	return res
 }
+
+// Findings ... : 모든 분석기에서 탐지된 보안약점 목록 (panic 이 발생한 분석기의 결과는 제외)
+func (cca *ChainCodeAnalyzer) Findings() []Finding {
+	var res []Finding
+	if !cca.failed[astDetector] {
+		res = append(res, cca.astAnalyzer.findings...)
+	}
+
+	var graphFindings []Finding
+	for _, d := range []struct {
+		detector string
+		findings []Finding
+	}{
+		{gfDetector, cca.gfAnalyzer.findings},
+		{uiaDetector, cca.uiaAnalyzer.findings},
+		{ueDetector, cca.ueAnalyzer.findings},
+		{rywDetector, cca.rywAnalyzer.findings},
+		{rngDetector, cca.rngAnalyzer.findings},
+		{icDetector, cca.icAnalyzer.findings},
+		{pdDetector, cca.pdAnalyzer.findings},
+		{wwDetector, cca.wwAnalyzer.findings},
+		{itDetector, cca.itAnalyzer.findings},
+		{alDetector, cca.alAnalyzer.findings},
+		{iaDetector, cca.iaAnalyzer.findings},
+		{tsDetector, cca.tsAnalyzer.findings},
+	} {
+		if !cca.failed[d.detector] {
+			graphFindings = append(graphFindings, d.findings...)
+		}
+	}
+
+	// graph 기반 분석은 함수 단위로 수행되므로 분석 중인 함수를 기록
+	for i := range graphFindings {
+		if graphFindings[i].Function == "" {
+			graphFindings[i].Function = cca.funcName
+		}
+	}
+
+	return append(res, graphFindings...)
+}
 func (cca *ChainCodeAnalyzer) WeaknessAnalysis(f *ast.File, info *types.Info, block cfg.CFGBlock) {
 	if cca.isFirstAnalysis {
-		cca.astAnalyzer.Analysis(f, info)
+		cca.analyze(astDetector, func() { cca.astAnalyzer.Analysis(f, info) })
 		cca.isFirstAnalysis = false
 
 		cca.ueAnalyzer.SetErrTable(cca.astAnalyzer.FuncRetTable)
@@ -59,27 +206,79 @@ func (cca *ChainCodeAnalyzer) WeaknessAnalysis(f *ast.File, info *types.Info, bl
 
 	switch b := block.(type) {
 	case *cfg.BasicBlock:
-		cca.gfAnalyzer.GFDeclAnalysis(b)
-		cca.uiaAnalyzer.UIAAnalysis(b)
-		cca.ueAnalyzer.UEAnalysis(b)
+		cca.analyze(gfDetector, func() { cca.gfAnalyzer.GFDeclAnalysis(b) })
+		cca.analyze(uiaDetector, func() { cca.uiaAnalyzer.UIAAnalysis(b) })
+		cca.analyze(ueDetector, func() { cca.ueAnalyzer.UEAnalysis(b) })
+		cca.analyze(alDetector, func() { cca.alAnalyzer.ALAnalysis(b) })
+		cca.analyze(iaDetector, func() { cca.iaAnalyzer.IAAnalysis(f, info, b) })
 
 		if b.LinkedBlock() != nil {
 			cca.WeaknessAnalysis(f, info, b.LinkedBlock())
 		}
 	case *cfg.BranchBlock:
 		befRNGAnalyzer := cca.rngAnalyzer.Copy()
+		befRYWAnalyzer := cca.rywAnalyzer.Copy()
+		befWWAnalyzer := cca.wwAnalyzer.Copy()
+		befITAnalyzer := cca.itAnalyzer.Copy()
+		befALAnalyzer := cca.alAnalyzer.Copy()
+		befIAAnalyzer := cca.iaAnalyzer.Copy()
+		cca.analyze(tsDetector, func() { cca.tsAnalyzer.TSAnalysis(f, info, b) })
 		if b.UjpBlock() != nil {
-
+			cca.analyze(itDetector, func() { cca.itAnalyzer.Branch(b, true) })
+			cca.analyze(alDetector, func() { cca.alAnalyzer.Branch(b, true) })
+			cca.analyze(iaDetector, func() { cca.iaAnalyzer.Branch(f, b, true) })
 			cca.WeaknessAnalysis(f, info, b.UjpBlock())
 		}
 
+		// 분기 이전의 rand.Seed 호출 여부로 되돌리고 탐지 결과는 유지
+		befRNGAnalyzer.analysisCount = cca.rngAnalyzer.analysisCount
+		befRNGAnalyzer.findings = cca.rngAnalyzer.findings
 		cca.rngAnalyzer = befRNGAnalyzer
-		if b.TargetBlock() != nil {
+
+		// 다른 분기에서 호출된 PutState, DelState 는 같은 실행 경로가 아니므로 분기 이전의 쓰기 목록으로 되돌림
+		befRYWAnalyzer.analysisCount = cca.rywAnalyzer.analysisCount
+		befRYWAnalyzer.findings = cca.rywAnalyzer.findings
+		befRYWAnalyzer.solverErr = cca.rywAnalyzer.solverErr
+		cca.rywAnalyzer = befRYWAnalyzer
+
+		befWWAnalyzer.analysisCount = cca.wwAnalyzer.analysisCount
+		befWWAnalyzer.findings = cca.wwAnalyzer.findings
+		befWWAnalyzer.solverErr = cca.wwAnalyzer.solverErr
+		cca.wwAnalyzer = befWWAnalyzer
+
+		// 다른 분기에서 닫힌 iterator 는 이 경로에서 열려 있으므로 분기 이전의 iterator 목록으로 되돌림
+		befITAnalyzer.analysisCount = cca.itAnalyzer.analysisCount
+		befITAnalyzer.findings = cca.itAnalyzer.findings
+		cca.itAnalyzer = befITAnalyzer
+
+		// len(args) 비교로 보장되는 인자 개수는 분기마다 다르므로 분기 이전의 최소 길이로 되돌림
+		befALAnalyzer.analysisCount = cca.alAnalyzer.analysisCount
+		befALAnalyzer.findings = cca.alAnalyzer.findings
+		cca.alAnalyzer = befALAnalyzer
+
+		// 분기 조건과 계산된 산술 연산은 분기마다 다르므로 분기 이전의 조건, 연산 목록으로 되돌림
+		befIAAnalyzer.analysisCount = cca.iaAnalyzer.analysisCount
+		befIAAnalyzer.findings = cca.iaAnalyzer.findings
+		befIAAnalyzer.solverErr = cca.iaAnalyzer.solverErr
+		cca.iaAnalyzer = befIAAnalyzer
+
+		// 반복문의 back edge (이전 block 으로의 분기) 는 따라가지 않음 (반복문 본문은 이미 분석됨)
+		// 따라가면 본문에 분기가 있는 반복문 (ex : testSrc/unarytest.go 의 gcd) 에서 재귀 호출이 끝나지 않음
+		if b.TargetBlock() != nil && b.TargetBlock().BlockNumber() > b.BlockNumber() {
+			cca.analyze(itDetector, func() { cca.itAnalyzer.Branch(b, false) })
+			cca.analyze(alDetector, func() { cca.alAnalyzer.Branch(b, false) })
+			cca.analyze(iaDetector, func() { cca.iaAnalyzer.Branch(f, b, false) })
 			cca.WeaknessAnalysis(f, info, b.TargetBlock())
 		}
 	case *cfg.CallBlock:
-		cca.rywAnalyzer.RYWAnalysisUsedZ3(b)
-		cca.rngAnalyzer.RNGAnalysis(b)
+		cca.analyze(rywDetector, func() { cca.rywAnalyzer.RYWAnalysisUsedZ3(b) })
+		cca.analyze(rngDetector, func() { cca.rngAnalyzer.RNGAnalysis(b) })
+		cca.analyze(icDetector, func() { cca.icAnalyzer.ICAnalysis(b) })
+		cca.analyze(pdDetector, func() { cca.pdAnalyzer.PDAnalysis(b) })
+		cca.analyze(wwDetector, func() { cca.wwAnalyzer.WWAnalysis(b) })
+		cca.analyze(itDetector, func() { cca.itAnalyzer.ITAnalysis(f, b) })
+		cca.analyze(iaDetector, func() { cca.iaAnalyzer.IAAnalysis(f, info, b) })
+		cca.analyze(tsDetector, func() { cca.tsAnalyzer.TSAnalysis(f, info, b) })
 
 		if b.TargetBlock() != nil {
 			cca.WeaknessAnalysis(f, info, b.TargetBlock())
@@ -89,6 +288,8 @@ func (cca *ChainCodeAnalyzer) WeaknessAnalysis(f *ast.File, info *types.Info, bl
 			cca.WeaknessAnalysis(f, info, b.UjpBlock())
 		}
 	case *cfg.ReturnBlock:
+		cca.analyze(itDetector, func() { cca.itAnalyzer.ITAnalysis(f, b) })
+		cca.analyze(tsDetector, func() { cca.tsAnalyzer.TSAnalysis(f, info, b) })
 		if b.LinkedBlock() != nil {
 			cca.WeaknessAnalysis(f, info, b.LinkedBlock())
 		}
@@ -8,6 +8,9 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"sort"
+	"strconv"
+	"strings"
 
 	"WAH_prototype_go-master/Src/analysisGraph/cfg"
 	"WAH_prototype_go-master/Src/analysisGraph/vfg"
@@ -16,14 +19,273 @@ import (
 )
 
 // Analysis ... : 보안약점 항목을 검사할 때 외부에서 사용하는 함수
-func Analysis(fs *token.FileSet, f *ast.File, info *types.Info, analysisFile string, block cfg.CFGBlock, chain vfg.DUChain, codeList []icg.CodeInfo,litTable *symbolTable.LiteralTable) {
+// funcName 함수의 CFG (block) 를 분석하고 탐지된 보안약점 목록을 반환
+func Analysis(fs *token.FileSet, f *ast.File, info *types.Info, analysisFile string, funcName string, block cfg.CFGBlock, chain vfg.DUChain, codeList []icg.CodeInfo,litTable *symbolTable.LiteralTable) []Finding {
 	analyzer := new(ChainCodeAnalyzer)
-	analyzer.Init(fs, analysisFile, chain, codeList,litTable)
+	analyzer.Init(fs, analysisFile, funcName, chain, codeList,litTable)
 
 	analyzer.WeaknessAnalysis(f, info, block)
-	totalCount := analyzer.TotalCount()
 
-	fmt.Printf("\t Total weakness count : %d\n",totalCount)
+	if f == nil {
+		return analyzer.Findings()
+	}
+	// 함수 하나만 분석하므로 오래된 주석은 보고하지 않음
+	findings, _, _ := ApplySuppressions(analyzer.Findings(), CollectSuppressions(fs, []*ast.File{f}))
+	return findings
+}
+
+// AnalysisPackage ... : 여러 파일로 구성된 패키지 단위 분석
+// AST 분석은 파일마다 한 번, graph 분석은 함수마다 한 번 수행하며 함수가 선언된 파일로 결과를 기록
+// 모든 분석기의 결과에 프로젝트 설정 (config, nil 이면 기본 설정) 과 //wah:ignore 주석을 적용
+// 분석 중 오류가 발생한 파일 또는 함수는 Report.Errors 에 기록하고 나머지 분석을 계속 진행
+func AnalysisPackage(fs *token.FileSet, files []*ast.File, info *types.Info, silTable *icg.SILTable, controlFlowGraphs map[int]cfg.CFGBlock, duChainofFunctions map[int]vfg.DUChain, litTable *symbolTable.LiteralTable, config *Config) Report {
+	// 파일이 없는 패키지는 분석할 대상이 없음 (패키지 단위 분석은 첫 번째 파일의 위치로 오류를 기록)
+	if len(files) == 0 {
+		return Report{}
+	}
+
+	var findings []Finding
+	var errs []AnalysisError
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	// 다른 파일에서 호출되는 함수의 error 반환 위치도 알 수 있도록 패키지 전체의 결과를 합침
+	errTable := make(map[string]int)
+	astFiles := make(map[string]*ast.File)
+	for _, f := range files {
+		fileName := fs.Position(f.Package).Filename
+		astFiles[fileName] = f
+		analyzeSafely(&errs, fileName, "", func() {
+			astAnalyzer := new(ASTAnalyzer)
+			astAnalyzer.Init(fileName, fs)
+			astAnalyzer.SetConfig(config)
+			astAnalyzer.SetFiles(files)
+			astAnalyzer.Analysis(f, info)
+
+			for funcName, errLocation := range astAnalyzer.FuncRetTable {
+				errTable[funcName] = errLocation
+			}
+			findings = AppendFindings(findings, astAnalyzer.Findings()...)
+		})
+	}
+
+	// 트랜잭션 함수의 client identity 확인, panic 은 진입 함수 (Invoke, contract 의 트랜잭션 함수) 에서 호출되는 함수를 따라가므로 패키지 단위로 분석
+	analyzeSafely(&errs, fs.Position(files[0].Package).Filename, "", func() {
+		acAnalyzer := new(ACAnalyzer)
+		acAnalyzer.Init(fs, info)
+		acAnalyzer.config = config
+		acAnalyzer.Analysis(files)
+		findings = AppendFindings(findings, acAnalyzer.Findings()...)
+	})
+	analyzeSafely(&errs, fs.Position(files[0].Package).Filename, "", func() {
+		rpAnalyzer := new(RPAnalyzer)
+		rpAnalyzer.Init(fs, info)
+		rpAnalyzer.config = config
+		rpAnalyzer.Analysis(files)
+		findings = AppendFindings(findings, rpAnalyzer.Findings()...)
+	})
+	// 패키지 수준 변수, receiver field 는 다른 함수 (트랜잭션) 에서 쓴 값을 읽는지 확인해야 하므로 패키지 단위로 분석
+	analyzeSafely(&errs, fs.Position(files[0].Package).Filename, "", func() {
+		if silTable == nil {
+			return
+		}
+		gsAnalyzer := new(GSAnalyzer)
+		gsAnalyzer.Init(fs, info)
+		gsAnalyzer.config = config
+		gsAnalyzer.Analysis(files, silTable)
+		findings = AppendFindings(findings, gsAnalyzer.Findings()...)
+	})
+
+	var funcKeys []int
+	for k := range controlFlowGraphs {
+		funcKeys = append(funcKeys, k)
+	}
+	sort.Ints(funcKeys)
+
+	for _, k := range funcKeys {
+		fileName, funcName := silTable.FunctionFile(k), silTable.StringPool().LookupSymbolName(k)
+		analyzeSafely(&errs, fileName, funcName, func() {
+			analyzer := new(ChainCodeAnalyzer)
+			analyzer.Init(fs, fileName, funcName, duChainofFunctions[k], silTable.FunctionCodeTable()[k], litTable)
+			analyzer.SetErrTable(errTable)
+			analyzer.SetConfig(config)
+
+			// AST 분석은 위에서 수행했으므로 함수가 선언된 파일은 graph 분석 (ex : 변수 이름 검색) 에만 사용
+			// panic 이 발생한 분석기의 결과만 버리고 나머지 분석기의 결과는 보고
+			analyzer.WeaknessAnalysis(astFiles[fileName], info, controlFlowGraphs[k])
+			findings = AppendFindings(findings, analyzer.Findings()...)
+			// z3 가 설치되지 않은 경우 같은 분석 오류는 한 번만 기록
+			for _, e := range analyzer.Errors() {
+				if !containsError(errs, e) {
+					errs = append(errs, e)
+				}
+			}
+		})
+	}
+
+	findings = config.apply(findings)
+	fingerprintFindings(findings)
+
+	var report Report
+	report.Errors = errs
+	report.Findings, report.Suppressed, report.Stale = ApplySuppressions(findings, CollectSuppressions(fs, files))
+
+	// 사용하지 않는 보안약점에 대한 주석은 오래된 주석으로 보고하지 않음
+	var stale []Suppression
+	for _, s := range report.Stale {
+		var ids []string
+		for _, id := range s.IDs {
+			if ccw, ok := lookupCCW(id); !ok || config.IsEnabled(ccw) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 || len(s.IDs) == 0 {
+			s.IDs = ids
+			stale = append(stale, s)
+		}
+	}
+	report.Stale = stale
+	return report
+}
+
+// AnalysisFiles ... : 타입 검사가 끝난 패키지의 파일들로 SIL, CFG, DUChain 을 생성한 뒤 AnalysisPackage 수행
+// (go/analysis 처럼 이미 파싱, 타입 검사된 패키지를 전달받는 경우 사용)
+// SIL, CFG, DUChain 을 생성하지 못한 패키지는 분석 오류를 기록하고 AST 기반 분석만 수행
+func AnalysisFiles(fs *token.FileSet, files []*ast.File, info *types.Info, config *Config) Report {
+	if len(files) == 0 {
+		return Report{}
+	}
+
+	_, litTable, silTable, controlFlowGraphs, duChainofFunctions, err := GenerateGraphs(fs, files, info)
+	report := AnalysisPackage(fs, files, info, silTable, controlFlowGraphs, duChainofFunctions, litTable, config)
+	if err != nil {
+		report.Errors = append([]AnalysisError{{File: fs.Position(files[0].Package).Filename, Message: err.Error()}}, report.Errors...)
+	}
+	return report
+}
+
+// GenerateGraphs ... : 패키지의 파일들로 SIL, CFG, DUChain 생성
+// 생성 중 발생한 panic (ex : icg 가 지원하지 않는 문법) 은 오류로 반환하고 생성 결과는 모두 버림
+func GenerateGraphs(fs *token.FileSet, files []*ast.File, info *types.Info) (strPool *symbolTable.StringPool, litTable *symbolTable.LiteralTable, silTable *icg.SILTable, controlFlowGraphs map[int]cfg.CFGBlock, duChainofFunctions map[int]vfg.DUChain, err error) {
+	stage := "symbol table"
+	defer func() {
+		if r := recover(); r != nil {
+			strPool, litTable, silTable, controlFlowGraphs, duChainofFunctions = nil, nil, nil, nil, nil
+			err = fmt.Errorf("%s generation failed, the SIL/CFG/DU chain based checks are skipped: %v", stage, r)
+		}
+	}()
+
+	strPoolGenerator := &symbolTable.StringPoolGenerator{}
+	strPoolGenerator.Init(info)
+	strPool, symTble, litTable := strPoolGenerator.GenFiles(files)
+
+	stage = "SIL"
+	silTable = icg.CodeGenFiles(files, fs, info, strPool, symTble, litTable)
+	stage = "CFG"
+	controlFlowGraphs = cfg.Generate(silTable)
+	stage = "DU chain"
+	duChainofFunctions = vfg.Generate(controlFlowGraphs)
+	return strPool, litTable, silTable, controlFlowGraphs, duChainofFunctions, nil
+}
+
+// containsError ... : errs 에 같은 분석 오류가 있는지 여부
+func containsError(errs []AnalysisError, e AnalysisError) bool {
+	for _, err := range errs {
+		if err == e {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeSafely ... : 분석 중 발생한 panic 을 분석 오류로 기록 (해당 파일 또는 함수의 결과는 버림)
+func analyzeSafely(errs *[]AnalysisError, file string, funcName string, analyze func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			*errs = append(*errs, AnalysisError{File: file, Function: funcName, Message: fmt.Sprint(r)})
+		}
+	}()
+	analyze()
+}
+
+// transactionFuncs ... : 패키지의 모든 함수 선언과 트랜잭션 진입 함수 목록
+// 진입 함수는 chaincode 의 Invoke 메소드와 contractapi.Contract 를 embedding 한 contract 의 exported 메소드
+func transactionFuncs(files []*ast.File, info *types.Info) (map[*types.Func]*ast.FuncDecl, []*ast.FuncDecl) {
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	var entries []*ast.FuncDecl
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			obj, ok := info.Defs[funcDecl.Name].(*types.Func)
+			if ok {
+				decls[obj] = funcDecl
+			}
+			if funcDecl.Recv != nil && (funcDecl.Name.Name == "Invoke" || (ok && isContractTransaction(obj))) {
+				entries = append(entries, funcDecl)
+			}
+		}
+	}
+	return decls, entries
+}
+
+// contractPackage ... : contract 를 작성하는 fabric-contract-api-go 의 패키지
+const contractPackage = "github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+// contractMethods ... : contractapi.Contract 가 구현하는 ContractInterface 등의 메소드 (트랜잭션으로 호출되지 않음)
+var contractMethods = map[string]bool{
+	"GetName":                      true,
+	"GetInfo":                      true,
+	"GetUnknownTransaction":        true,
+	"GetBeforeTransaction":         true,
+	"GetAfterTransaction":          true,
+	"GetTransactionContextHandler": true,
+	"GetIgnoredFunctions":          true,
+	"GetEvaluateTransactions":      true,
+}
+
+// isContract ... : t 가 contractapi.Contract 이거나 contractapi.Contract 를 (다른 struct 를 통해서라도) embedding 하는지 여부
+func isContract(t types.Type, visited map[types.Type]bool) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || visited[named] {
+		return false
+	}
+	visited[named] = true
+	if obj := named.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == contractPackage && obj.Name() == "Contract" {
+		return true
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Embedded() && isContract(field.Type(), visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isContractTransaction ... : f 가 contract 의 트랜잭션 함수 (contract type 에 선언된 exported 메소드) 인지 여부
+func isContractTransaction(f *types.Func) bool {
+	recv := f.Type().(*types.Signature).Recv()
+	if recv == nil || !f.Exported() || contractMethods[f.Name()] {
+		return false
+	}
+	return isContract(recv.Type(), make(map[types.Type]bool))
+}
+
+// isTransactionContext ... : t 가 트랜잭션 context (contractapi.TransactionContextInterface 와 같이 GetStub 메소드가 있는 type) 인지 여부
+func isTransactionContext(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, "GetStub")
+	_, ok := obj.(*types.Func)
+	return ok
 }
 
 /* findRhsList ... : code list를 역해석 하여 lhs (definition)에 할당에 사용된 rhs (use)리스트를 찾는 함수
@@ -32,6 +294,9 @@ func Analysis(fs *token.FileSet, f *ast.File, info *types.Info, analysisFile str
 func FindRhsList(defLine int, codeList []icg.CodeInfo) []icg.CodeInfo {
 	reverseCodeList := sliceReverse(codeList)
 	defCodeIndex := findSILIndex(reverseCodeList, defLine)
+	if defCodeIndex == -1 { // 함수 안에 정의가 없는 경우 (매개변수, 전역 변수)
+		return nil
+	}
 
 	analysisRange := reverseCodeList[defCodeIndex:]
 	sp := -analysisRange[0].GetPopParameterNum() //pop이 소모자, push가 판매
@@ -60,7 +325,7 @@ func FindRhsList(defLine int, codeList []icg.CodeInfo) []icg.CodeInfo {
 			if isDebug {
 				fmt.Printf("Bottom up analysis completed!\n")
 				fmt.Printf("\t definition code : %s\n \t target code list : %s\n\n", analysisRange[0].String(), fmt.Sprint(res))
-				fmt.Println("--------------------------------------------------------------------------------\n")
+				fmt.Print("--------------------------------------------------------------------------------\n\n")
 			}
 			break
 		}
@@ -72,6 +337,9 @@ func FindRhsList(defLine int, codeList []icg.CodeInfo) []icg.CodeInfo {
 func FindLhsList(defLine int, codeList []icg.CodeInfo) []icg.CodeInfo {
 
 	defCodeIndex := findSILIndex(codeList, defLine)
+	if defCodeIndex == -1 { // 함수 안에 정의가 없는 경우 (매개변수, 전역 변수)
+		return nil
+	}
 
 	analysisRange := codeList[defCodeIndex:]
 	sp := analysisRange[0].GetPushParameterNum() //pop이 소모자, push가 판매
@@ -96,7 +364,7 @@ func FindLhsList(defLine int, codeList []icg.CodeInfo) []icg.CodeInfo {
 				fmt.Printf("\tSatisfaction value for foward analysis (if 0, analysis ends) : %d\n\n", sp)
 				fmt.Printf("analysis completed!\n")
 				fmt.Printf("\t definition code : %s\n \t target code list : %s\n\n", analysisRange[0].String(), fmt.Sprint(res))
-				fmt.Println("--------------------------------------------------------------------------------\n")
+				fmt.Print("--------------------------------------------------------------------------------\n\n")
 			}
 			break
 		}
@@ -114,7 +382,7 @@ func FindLhsList(defLine int, codeList []icg.CodeInfo) []icg.CodeInfo {
 			if isDebug {
 				fmt.Printf("analysis completed!\n")
 				fmt.Printf("\t definition code : %s\n \t target code list : %s\n\n", analysisRange[0].String(), fmt.Sprint(res))
-				fmt.Println("--------------------------------------------------------------------------------\n")
+				fmt.Print("--------------------------------------------------------------------------------\n\n")
 			}
 			break
 		}
@@ -142,3 +410,166 @@ func findSILIndex(codeInfo []icg.CodeInfo, line int) int {
 	}
 	return res
 }
+
+// CallArguments ... : call 명령어의 인자별 SIL 코드 (첫 번째 인자부터)
+// call 이전의 코드를 역방향으로 분석하여 스택에 값 하나를 push 하는 구간을 인자 하나로 분리하며, ldp 에서 분석 종료
+// 인자로 사용된 함수 호출 (ldp ~ call) 은 값 하나로 취급
+func CallArguments(call icg.CodeInfo, codeList []icg.CodeInfo) [][]icg.CodeInfo {
+	callIndex := findSILIndex(codeList, call.GetLine())
+	if callIndex == -1 {
+		return nil
+	}
+
+	var args [][]icg.CodeInfo
+	var arg []icg.CodeInfo
+	sp, nestedCall := 0, 0
+	for i := callIndex - 1; i >= 0; i-- {
+		code := codeList[i]
+		arg = append([]icg.CodeInfo{code}, arg...)
+
+		switch {
+		case code.Opcode() == icg.Call:
+			nestedCall++
+			continue
+		case code.Opcode() == icg.Ldp:
+			if nestedCall == 0 {
+				return args
+			}
+			nestedCall--
+			if nestedCall > 0 {
+				continue
+			}
+			sp++
+		case nestedCall > 0:
+			continue
+		default:
+			sp = sp + code.GetPushParameterNum() - code.GetPopParameterNum()
+		}
+
+		if sp == 1 {
+			args = append([][]icg.CodeInfo{arg}, args...)
+			arg = nil
+			sp = 0
+		}
+	}
+	return args
+}
+
+// CallResults ... : call 명령어의 반환 값을 저장하는 str 코드 (첫 번째 반환 값부터)
+//  ex ) data, err := stub.GetPrivateData(...) 에서 data, err 의 str
+func CallResults(call icg.CodeInfo, codeList []icg.CodeInfo) []icg.CodeInfo {
+	callIndex := findSILIndex(codeList, call.GetLine())
+	if callIndex == -1 {
+		return nil
+	}
+
+	var res []icg.CodeInfo
+	for _, code := range codeList[callIndex+1:] {
+		stack, ok := code.(*icg.StackOpcode)
+		if !ok {
+			break
+		}
+		// 반환 값이 하나인 경우 반환 값 ($0) 을 lod 한 뒤 str
+		if stack.Opcode() == icg.Lod && strings.HasPrefix(fmt.Sprint(stack.Params().Front().Next().Value), "$") {
+			continue
+		}
+		if stack.Opcode() != icg.Str {
+			break
+		}
+		res = append(res, stack)
+	}
+	return res
+}
+
+// reachingDefinition ... : code 에서 사용된 offset 변수의 definition 라인
+// DUChain 에 use 로 기록되지 않는 주소 사용 (ex : 구조체 필드 접근의 lda) 은 가장 가까운 이전 str 을 definition 으로 사용
+func reachingDefinition(offset string, code icg.CodeInfo, chain vfg.DUChain, codeList []icg.CodeInfo) (int, bool) {
+	if def, ok := chain.LookUpDefOfUse(offset, code.GetLine()); ok {
+		return def, def != -1
+	}
+
+	for i := findSILIndex(codeList, code.GetLine()) - 1; i >= 0; i-- {
+		if str, ok := codeList[i].(*icg.StackOpcode); ok && str.Opcode() == icg.Str {
+			if fmt.Sprint(str.Params().Front().Next().Value) == offset {
+				return str.GetLine(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// TraceDefinition ... : codes 에서 사용된 변수의 definition 을 DUChain 으로 역추적하여 isSource 를 만족하는 definition 을 찾는 함수
+// codes 에서 가장 가까운 definition 부터 isSource 를 만족하는 definition 까지의 str 코드 목록을 반환
+//  ex ) b = a.Payload, c = b 일 때 PutState(key, c) 의 c 에서 a 까지 : [str c, str b, str a]
+func TraceDefinition(codes []icg.CodeInfo, chain vfg.DUChain, codeList []icg.CodeInfo, isSource func(def int) bool) ([]icg.CodeInfo, bool) {
+	return traceDefinition(codes, chain, codeList, isSource, make(map[int]bool))
+}
+
+func traceDefinition(codes []icg.CodeInfo, chain vfg.DUChain, codeList []icg.CodeInfo, isSource func(def int) bool, visited map[int]bool) ([]icg.CodeInfo, bool) {
+	for _, code := range codes {
+		variable, ok := code.(*icg.StackOpcode)
+		if !ok || (variable.Opcode() != icg.Lod && variable.Opcode() != icg.Lda) {
+			continue
+		}
+
+		// 문자열 literal (lda.p 0 @n) 은 변수가 아님
+		offset := fmt.Sprint(variable.Params().Front().Next().Value)
+		if strings.HasPrefix(offset, "@") {
+			continue
+		}
+		def, ok := reachingDefinition(offset, variable, chain, codeList)
+		if !ok || visited[def] {
+			continue
+		}
+		visited[def] = true
+
+		defCode := codeList[findSILIndex(codeList, def)]
+		if isSource(def) {
+			return []icg.CodeInfo{defCode}, true
+		}
+		if path, ok := traceDefinition(FindRhsList(def, codeList), chain, codeList, isSource, visited); ok {
+			return append([]icg.CodeInfo{defCode}, path...), true
+		}
+	}
+	return nil, false
+}
+
+// literalValue ... : 인자가 문자열 literal 이거나 문자열 literal 만 할당된 변수인 경우 그 값
+func literalValue(arg []icg.CodeInfo, chain vfg.DUChain, codeList []icg.CodeInfo, litTable *symbolTable.LiteralTable) (string, bool) {
+	if len(arg) != 1 || litTable == nil {
+		return "", false
+	}
+	code, ok := arg[0].(*icg.StackOpcode)
+	if !ok {
+		return "", false
+	}
+
+	switch {
+	case code.Opcode() == icg.Lda && code.Type() == icg.Sp:
+		address, err := strconv.Atoi(strings.TrimPrefix(fmt.Sprint(code.Params().Front().Next().Value), "@"))
+		if err != nil {
+			return "", false
+		}
+		lit, ok := litTable.GetLiteral(address)
+		if !ok {
+			return "", false
+		}
+		if value, err := strconv.Unquote(lit); err == nil {
+			return value, true
+		}
+		return lit, true
+	case code.Opcode() == icg.Lod:
+		offset := fmt.Sprint(code.Params().Front().Next().Value)
+		def, ok := reachingDefinition(offset, code, chain, codeList)
+		if !ok || def >= code.GetLine() {
+			return "", false
+		}
+		// definition 바로 앞의 코드가 literal 인 경우 (ex : channel := "ch")
+		defIndex := findSILIndex(codeList, def)
+		if defIndex < 1 {
+			return "", false
+		}
+		return literalValue(codeList[defIndex-1:defIndex], chain, codeList, litTable)
+	}
+	return "", false
+}
@@ -0,0 +1,263 @@
+package wah
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// RuleConfig ...
+// 보안약점 하나에 대한 설정 (사용 여부, 심각도)
+type RuleConfig struct {
+	Enabled  *bool  `yaml:"enabled" json:"enabled"`
+	Severity string `yaml:"severity" json:"severity"`
+}
+
+// Config ...
+// 프로젝트 설정 (.wah.yaml 또는 .wah.json)
+// 함수 목록은 호출되는 함수 이름 (ex : stub.PutState) 의 마지막 selector (ex : PutState, .PutState) 또는 전체 이름 (ex : shim.Error) 과 비교하며 기본 목록에 추가됨
+// "." 으로 끝나는 항목 (ex : cid.) 은 패키지 이름으로 비교하고 keyValidators 는 마지막 selector 가 항목으로 시작하는지 (ex : validate 는 validateKey) 로 비교
+// 외부 접근 목록 (systemCommands, timestampFuncs, networkAccess, fileAccess, environmentReads) 은 타입 정보로 찾은
+// 함수의 패키지 경로 (ex : os/exec) 또는 패키지 경로를 포함한 함수 이름 (ex : os.Getenv, io/ioutil.ReadFile) 과 비교
+type Config struct {
+	Rules map[string]RuleConfig `yaml:"rules" json:"rules"`
+
+	// 이 심각도 이상의 보안약점이 있으면 실패로 처리 ("low", "medium", "high" 또는 "none")
+	FailOn string `yaml:"failOn" json:"failOn"`
+
+	ErrorHandlers      []string `yaml:"errorHandlers" json:"errorHandlers"`           // error 처리로 인정하는 함수 (UNHANDLED_ERROR)
+	PutState           []string `yaml:"putState" json:"putState"`                     // ledger 쓰기 함수 (READ_YOUR_WRITE, GF_DECLARATION)
+	GetState           []string `yaml:"getState" json:"getState"`                     // ledger 읽기 함수 (READ_YOUR_WRITE, GF_DECLARATION)
+	DelState           []string `yaml:"delState" json:"delState"`                     // ledger 삭제 함수 (DUPLICATE_KEY_WRITE, READ_AFTER_DELETE)
+	PhantomReadQueries []string `yaml:"phantomReadQueries" json:"phantomReadQueries"` // PHANTOM_READS
+	RangeQueries       []string `yaml:"rangeQueries" json:"rangeQueries"`             // RANGE_QUERY_RISK
+	RangeReads         []string `yaml:"rangeReads" json:"rangeReads"`                 // startKey, endKey 로 읽는 함수 (READ_YOUR_WRITE, READ_AFTER_DELETE)
+	SystemCommands     []string `yaml:"systemCommands" json:"systemCommands"`         // SYSTEM_COMMANDS
+	TimestampFuncs     []string `yaml:"timestampFuncs" json:"timestampFuncs"`         // system time 을 읽는 함수 (SYSTEM_TIMESTAMP)
+	InvokeChaincode    []string `yaml:"invokeChaincode" json:"invokeChaincode"`       // CROSS_CHAINCODE_INVOCATION
+	PrivateDataReads   []string `yaml:"privateDataReads" json:"privateDataReads"`     // PRIVATE_DATA_LEAK
+	PublicSinks        []string `yaml:"publicSinks" json:"publicSinks"`               // PRIVATE_DATA_LEAK (putState 함수 포함)
+	IteratorQueries    []string `yaml:"iteratorQueries" json:"iteratorQueries"`       // iterator 를 반환하는 query 함수 (UNCLOSED_ITERATOR)
+	ArgumentSources    []string `yaml:"argumentSources" json:"argumentSources"`       // 인자 slice 를 반환하는 함수 (UNCHECKED_ARGUMENT_LENGTH)
+	UnorderedEncoders  []string `yaml:"unorderedEncoders" json:"unorderedEncoders"`   // map 의 key 순서를 보장하지 않는 직렬화 함수 (MAP_SERIALIZATION)
+	PrivateDataWrites  []string `yaml:"privateDataWrites" json:"privateDataWrites"`   // private data 쓰기, 삭제 함수 (MISSING_ACCESS_CONTROL)
+	IdentityChecks     []string `yaml:"identityChecks" json:"identityChecks"`         // client identity 를 확인하는 함수 (MISSING_ACCESS_CONTROL)
+	NetworkAccess      []string `yaml:"networkAccess" json:"networkAccess"`           // NETWORK_ACCESS
+	FileAccess         []string `yaml:"fileAccess" json:"fileAccess"`                 // FILE_ACCESS
+	EnvironmentReads   []string `yaml:"environmentReads" json:"environmentReads"`     // ENVIRONMENT_ACCESS
+	PanicFuncs         []string `yaml:"panicFuncs" json:"panicFuncs"`                 // panic 을 일으키는 함수 (RUNTIME_PANIC)
+	ExitFuncs          []string `yaml:"exitFuncs" json:"exitFuncs"`                   // process 를 종료하는 함수 (RUNTIME_PANIC)
+	ArithmeticSources  []string `yaml:"arithmeticSources" json:"arithmeticSources"`   // ledger, 인자의 문자열을 정수로 변환하는 함수 (UNCHECKED_ARITHMETIC)
+	TransientSources   []string `yaml:"transientSources" json:"transientSources"`     // transient data 를 반환하는 함수 (UNVALIDATED_KEY_INPUT)
+	KeySinks           []string `yaml:"keySinks" json:"keySinks"`                     // ledger key 를 인자로 받는 함수 (UNVALIDATED_KEY_INPUT)
+	KeyValidators      []string `yaml:"keyValidators" json:"keyValidators"`           // key 로 사용할 입력을 검증하는 함수 (UNVALIDATED_KEY_INPUT)
+
+	disabled map[CCW]bool
+	severity map[CCW]Severity
+	failOn   Severity
+}
+
+// ConfigFileNames ... : 설정 파일을 지정하지 않은 경우 찾는 파일 이름
+var ConfigFileNames = []string{".wah.yaml", ".wah.yml", ".wah.json"}
+
+// DefaultConfig ... : 설정 파일이 없는 경우의 기본 설정
+// 사용자 설정이 없으므로 Init 이 실패하는 경우는 기본 설정이 잘못된 경우뿐임
+func DefaultConfig() *Config {
+	config := new(Config)
+	if err := config.Init(); err != nil {
+		panic(fmt.Sprintf("wah: invalid default config: %v", err))
+	}
+	return config
+}
+
+// Init ... : 사용자 설정 앞에 기본 함수 목록을 추가하고 규칙 설정을 검사
+func (config *Config) Init() error {
+	config.ErrorHandlers = append([]string{"shim.Error", "log.Fatal", "log.Fatalf", "log.Fatalln"}, config.ErrorHandlers...)
+	config.PutState = append([]string{".PutState"}, config.PutState...)
+	config.GetState = append([]string{".GetState"}, config.GetState...)
+	config.DelState = append([]string{".DelState"}, config.DelState...)
+	config.PhantomReadQueries = append([]string{"GetHistoryForKey", "GetQueryResult", "GetQueryResultWithPagination"}, config.PhantomReadQueries...)
+	config.RangeQueries = append([]string{"GetHistoryForKey", "GetQueryResult", "GetQueryResultWithPagination",
+		"GetStateByRangeWithPagination", "GetStateByPartialCompositeKeyWithPagination", "GetPrivateDataQueryResult"}, config.RangeQueries...)
+	config.RangeReads = append([]string{".GetStateByRange", ".GetStateByRangeWithPagination"}, config.RangeReads...)
+	config.SystemCommands = append([]string{"os/exec", "os.StartProcess", "syscall.Exec", "syscall.ForkExec"}, config.SystemCommands...)
+	config.TimestampFuncs = append([]string{"time.Now", "time.Since", "time.Until"}, config.TimestampFuncs...)
+	config.InvokeChaincode = append([]string{".InvokeChaincode"}, config.InvokeChaincode...)
+	config.PrivateDataReads = append([]string{".GetPrivateData", ".GetPrivateDataHash"}, config.PrivateDataReads...)
+	config.PublicSinks = append([]string{".SetEvent", "shim.Success"}, config.PublicSinks...)
+	config.IteratorQueries = append([]string{".GetStateByRange", ".GetStateByPartialCompositeKey", ".GetQueryResult", ".GetHistoryForKey",
+		".GetStateByRangeWithPagination", ".GetStateByPartialCompositeKeyWithPagination", ".GetQueryResultWithPagination",
+		".GetPrivateDataByRange", ".GetPrivateDataByPartialCompositeKey", ".GetPrivateDataQueryResult"}, config.IteratorQueries...)
+	config.ArgumentSources = append([]string{".GetStringArgs", ".GetArgs", ".GetFunctionAndParameters"}, config.ArgumentSources...)
+	config.UnorderedEncoders = append([]string{"(*encoding/gob.Encoder).Encode", "github.com/golang/protobuf/proto.Marshal", "google.golang.org/protobuf/proto.Marshal"}, config.UnorderedEncoders...)
+	config.PrivateDataWrites = append([]string{".PutPrivateData", ".DelPrivateData"}, config.PrivateDataWrites...)
+	config.IdentityChecks = append([]string{".GetCreator", "cid.", "GetClientIdentity", ".GetMSPID", ".GetAttributeValue", ".AssertAttributeValue"}, config.IdentityChecks...)
+	config.NetworkAccess = append([]string{"net/http", "net/rpc", "net.Dial", "net.DialTimeout", "net.DialTCP", "net.DialUDP", "net.Listen",
+		"net.LookupHost", "net.LookupIP", "net.LookupAddr", "(*net.Dialer).Dial", "(*net.Dialer).DialContext",
+		"(*net/http.Client).Do", "(*net/http.Client).Get", "(*net/http.Client).Post", "(*net/http.Client).PostForm", "(*net/http.Client).Head"}, config.NetworkAccess...)
+	config.FileAccess = append([]string{"os.Open", "os.OpenFile", "os.Create", "os.ReadFile", "os.WriteFile", "os.ReadDir", "os.Stat", "os.Lstat",
+		"os.Remove", "os.RemoveAll", "os.Mkdir", "os.MkdirAll", "os.Rename", "io/ioutil.ReadFile", "io/ioutil.WriteFile", "io/ioutil.ReadDir",
+		"io/ioutil.TempFile", "io/ioutil.TempDir", "path/filepath.Walk", "path/filepath.Glob"}, config.FileAccess...)
+	config.EnvironmentReads = append([]string{"os.Getenv", "os.LookupEnv", "os.Environ", "os.ExpandEnv", "os.Hostname", "os.Getwd",
+		"os.Getpid", "os.Getuid", "syscall.Getenv"}, config.EnvironmentReads...)
+	config.PanicFuncs = append([]string{"log.Panic", "log.Panicf", "log.Panicln", "(*log.Logger).Panic", "(*log.Logger).Panicf", "(*log.Logger).Panicln"}, config.PanicFuncs...)
+	config.ExitFuncs = append([]string{"os.Exit", "syscall.Exit", "log.Fatal", "log.Fatalf", "log.Fatalln",
+		"(*log.Logger).Fatal", "(*log.Logger).Fatalf", "(*log.Logger).Fatalln"}, config.ExitFuncs...)
+	config.TransientSources = append([]string{".GetTransient"}, config.TransientSources...)
+	config.KeySinks = append([]string{".PutState", ".DelState", ".CreateCompositeKey", ".GetStateByRange", ".PutPrivateData", ".DelPrivateData"}, config.KeySinks...)
+	config.KeyValidators = append([]string{"validate", "Validate", "sanitize", "Sanitize"}, config.KeyValidators...)
+	config.ArithmeticSources = append([]string{"strconv.Atoi", "strconv.ParseInt", "strconv.ParseUint"}, config.ArithmeticSources...)
+
+	failOn, err := ParseFailOn(config.FailOn)
+	if err != nil {
+		return fmt.Errorf("failOn: %v", err)
+	}
+	config.failOn = failOn
+
+	config.disabled = make(map[CCW]bool)
+	config.severity = make(map[CCW]Severity)
+	for id, rule := range config.Rules {
+		ccw, ok := lookupCCW(normalizeCCWID(id))
+		if !ok {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+		if rule.Enabled != nil && !*rule.Enabled {
+			config.disabled[ccw] = true
+		}
+		if rule.Severity != "" {
+			severity, err := ParseSeverity(rule.Severity)
+			if err != nil {
+				return fmt.Errorf("rule %q: %v", id, err)
+			}
+			config.severity[ccw] = severity
+		}
+	}
+	return nil
+}
+
+// LoadConfig ... : 설정 파일 읽기 (.json 확장자는 JSON, 그 외에는 YAML)
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := new(Config)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	} else {
+		err = yaml.UnmarshalStrict(data, config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read config %s: %v", path, err)
+	}
+
+	if err := config.Init(); err != nil {
+		return nil, fmt.Errorf("could not read config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// IsEnabled ... : 보안약점을 보고하는지 여부
+func (config *Config) IsEnabled(ccw CCW) bool {
+	return !config.disabled[ccw]
+}
+
+// Severity ... : 설정된 심각도 (설정이 없으면 기본 심각도)
+func (config *Config) Severity(ccw CCW) Severity {
+	if severity, ok := config.severity[ccw]; ok {
+		return severity
+	}
+	return ccw.DefaultSeverity()
+}
+
+// FailOnSeverity ... : 실패로 처리하는 최소 심각도 (0 이면 실패로 처리하지 않음)
+func (config *Config) FailOnSeverity() Severity {
+	return config.failOn
+}
+
+// ParseFailOn ... : 실패 기준 심각도 변환 ("" 또는 "none" 은 0)
+func ParseFailOn(s string) (Severity, error) {
+	if s == "" || strings.EqualFold(s, "none") {
+		return 0, nil
+	}
+	return ParseSeverity(s)
+}
+
+// apply ... : 사용하지 않는 보안약점을 제외하고 설정된 심각도를 적용
+// 심각도 설정이 없으면 분석기가 정한 심각도 (ex : CROSS_CHAINCODE_INVOCATION 의 channel 에 따른 심각도) 를 유지
+func (config *Config) apply(findings []Finding) []Finding {
+	var res []Finding
+	for _, f := range findings {
+		if !config.IsEnabled(f.CCW) {
+			continue
+		}
+		if severity, ok := config.severity[f.CCW]; ok {
+			f.Severity = severity
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// matchCall ... : 타입 정보로 찾은 함수의 패키지 경로 또는 패키지 경로를 포함한 함수 이름이 목록의 항목 중 하나와 같은지 여부
+// 패키지 경로는 패키지 수준의 함수만 비교 (메소드는 (*net/http.Client).Do 처럼 이름으로 지정)
+func matchCall(f *types.Func, list []string) bool {
+	if f == nil || f.Pkg() == nil {
+		return false
+	}
+	isMethod := f.Type().(*types.Signature).Recv() != nil
+	for _, name := range list {
+		if (!isMethod && f.Pkg().Path() == name) || f.FullName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorName ... : 함수 이름의 마지막 selector (ex : ctx.GetStub().PutState 는 PutState)
+func selectorName(funcName string) string {
+	return funcName[strings.LastIndex(funcName, ".")+1:]
+}
+
+// matchFunc ... : 함수 이름이 목록의 항목 중 하나와 일치하는지 여부
+// 항목이 "." 으로 끝나면 패키지 이름, "." 을 포함하면 전체 이름 (앞의 receiver 는 무시), 그 외에는 마지막 selector 와 비교
+// (GetState 는 GetStateByRange 와, validate 는 invalidate 와 일치하지 않음)
+func matchFunc(funcName string, list []string) bool {
+	for _, name := range list {
+		name = strings.TrimPrefix(name, ".")
+		switch {
+		case name == "":
+		case strings.HasSuffix(name, "."):
+			if strings.HasPrefix(funcName, name) {
+				return true
+			}
+		case strings.Contains(name, "."):
+			if funcName == name || strings.HasSuffix(funcName, "."+name) {
+				return true
+			}
+		case selectorName(funcName) == name:
+			return true
+		}
+	}
+	return false
+}
+
+// matchFuncPrefix ... : 함수 이름의 마지막 selector 가 목록의 항목 중 하나로 시작하는지 여부 (ex : validate 는 validateKey 와 일치)
+func matchFuncPrefix(funcName string, list []string) bool {
+	sel := selectorName(funcName)
+	for _, name := range list {
+		if name != "" && strings.HasPrefix(sel, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package wah
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Location ...
+// 보안약점과 관련된 부가 위치 (ex : READ_YOUR_WRITE 의 PutState 위치, GF_DECLARATION 의 전역 변수 체인)
+type Location struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Finding ...
+// 분석기가 탐지한 보안약점 하나에 대한 정보
+type Finding struct {
+	CCW      CCW        `json:"-"`
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	File     string     `json:"file"`
+	Line     int        `json:"line"`
+	Column   int        `json:"column,omitempty"`
+	Function string     `json:"function,omitempty"`
+	Severity Severity   `json:"severity"`
+	Message  string     `json:"message,omitempty"`
+	Related  []Location `json:"related,omitempty"`
+
+	// 분석기가 탐지 근거로 사용한 정보 (ex : READ_YOUR_WRITE 의 SMT formula)
+	Evidence string `json:"evidence,omitempty"`
+
+	// baseline 비교에 사용하는 fingerprint 와 정규화된 소스 코드
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+
+	// //wah:ignore 주석으로 억제된 경우 주석에 작성된 사유
+	Justification string `json:"justification,omitempty"`
+}
+
+// ID ... : CCW-00N 형식의 보안약점 식별자
+func (c CCW) ID() string {
+	return fmt.Sprintf("CCW-%03d", int(c))
+}
+
+// lookupCCW ... : CCW-00N 형식의 식별자에 해당하는 보안약점
+func lookupCCW(id string) (CCW, bool) {
+	for _, ccw := range CCWList() {
+		if ccw.ID() == id {
+			return ccw, true
+		}
+	}
+	return 0, false
+}
+
+func newFinding(ccw CCW, file string, line int, column int, message string) Finding {
+	return Finding{
+		CCW:      ccw,
+		ID:       ccw.ID(),
+		Name:     ccw.String(),
+		Severity: ccw.DefaultSeverity(),
+		File:     file,
+		Line:     line,
+		Column:   column,
+		Message:  message,
+	}
+}
+
+// AddRelated ... : 보안약점과 관련된 위치를 추가
+func (f *Finding) AddRelated(file string, line int, message string) {
+	f.Related = append(f.Related, Location{File: file, Line: line, Message: message})
+}
+
+func (f *Finding) isSame(target Finding) bool {
+	return f.CCW == target.CCW && f.File == target.File && f.Line == target.Line &&
+		f.Column == target.Column && f.Message == target.Message
+}
+
+// AppendFindings ... : 이미 존재하는 보안약점은 제외하고 list에 추가
+func AppendFindings(list []Finding, found ...Finding) []Finding {
+	for _, f := range found {
+		isExist := false
+		for _, exist := range list {
+			if exist.isSame(f) {
+				isExist = true
+				break
+			}
+		}
+		if !isExist {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// SortFindings ... : 파일, 라인, 컬럼, CCW 순으로 정렬 (출력 결과를 항상 같은 순서로 유지)
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.CCW < b.CCW
+	})
+}
@@ -2,7 +2,6 @@ package wah
 
 import (
 	"fmt"
-	"strings"
 
 	"WAH_prototype_go-master/Src/analysisGraph/cfg"
 	"WAH_prototype_go-master/Src/analysisGraph/vfg"
@@ -17,6 +16,8 @@ type GFDeclAnalyzer struct {
 	chain         vfg.DUChain
 	codeList      []icg.CodeInfo
 	analysisCount int
+	findings      []Finding
+	config        *Config
 }
 
 func (analyzer *GFDeclAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo) {
@@ -25,6 +26,7 @@ func (analyzer *GFDeclAnalyzer) Init(analysisFile string, chain vfg.DUChain, cod
 	analyzer.chain = chain
 	analyzer.codeList = codeList
 	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
 }
 
 func (analyer *GFDeclAnalyzer) IsGF(target icg.CodeInfo) bool {
@@ -111,6 +113,10 @@ func (analyzer *GFDeclAnalyzer) GFDeclAnalysis(block cfg.CFGBlock) int{
 					offsetStr := fmt.Sprint(arg.Params().Front().Next().Value)
 					addressOffset = append(addressOffset, offsetStr)
 				case icg.Ldi:
+					// pointer 가 가리키는 struct 의 field (lod, ldi.t, pop2) 는 lda 로 주소를 계산하지 않음
+					if len(addressOffset) == 0 {
+						break
+					}
 					offsetStr := addressOffset[len(addressOffset)-1]
 					def, _ := analyzer.chain.LookUpDefOfUse(offsetStr, sil.GetLine())
 					targets := FindRhsList(def, analyzer.codeList)
@@ -127,7 +133,9 @@ func (analyzer *GFDeclAnalyzer) GFDeclAnalysis(block cfg.CFGBlock) int{
 					}
 
 				case icg.Sti:
-					addressOffset = addressOffset[0 : len(addressOffset)-1]
+					if len(addressOffset) > 0 {
+						addressOffset = addressOffset[0 : len(addressOffset)-1]
+					}
 				}
 			}
 		}
@@ -138,29 +146,17 @@ func (analyzer *GFDeclAnalyzer) GFDeclAnalysis(block cfg.CFGBlock) int{
 		opcode := b.CodeList()[0]
 		if callOp, ok := opcode.(*icg.ControlOpcode); ok {
 			funcName := fmt.Sprint(callOp.Params().Front().Value)
-			if strings.Contains(funcName, ".PutState") || strings.Contains(funcName, ".GetState") {
+			if matchFunc(funcName, analyzer.config.PutState) || matchFunc(funcName, analyzer.config.GetState) {
 				var ccw CCW = GF_DECLARATION
 				analyzer.analysisCount ++
-				if isFirstDetect {
-					fmt.Printf("chaincode weakness detected:\n")
-					isFirstDetect = false
-				}
-				fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-				fmt.Printf("\t %s : %d\n", analyzer.analysisFile, callOp.GetSourceLine())
 
-				fmt.Printf("  \t The offset of global variable or receiver field used as parameter: %s \n", analyzer.gflst[len(analyzer.gflst)-1])
-				if len(analyzer.gflst) != 1 {
-					fmt.Printf("  \t\t")
-					for i := len(analyzer.gflst) - 1; i >= 0; i-- {
-						fmt.Printf("%s", analyzer.gflst[i])
-						if i != 0 {
-							fmt.Printf(" <- ")
-						} else {
-							fmt.Println()
-						}
-					}
+				message := fmt.Sprintf("The offset of global variable or receiver field used as parameter: %s", analyzer.gflst[len(analyzer.gflst)-1])
+				finding := newFinding(ccw, analyzer.analysisFile, callOp.GetSourceLine(), 0, message)
+				// 전역 변수 (또는 receiver field) 에서 매개변수까지의 체인
+				for i := len(analyzer.gflst) - 1; i >= 0; i-- {
+					finding.AddRelated(analyzer.analysisFile, analyzer.gfSourceLine[i], analyzer.gflst[i])
 				}
-				fmt.Println()
+				analyzer.findings = append(analyzer.findings, finding)
 			}
 		}
 		if analyzer.IsLodParams() {
@@ -0,0 +1,360 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"WAH_prototype_go-master/Src/icg"
+	"WAH_prototype_go-master/Src/icg/symbolTable"
+)
+
+// GSAnalyzer ...
+// 트랜잭션 진입 함수 (Invoke, Init, contract 의 트랜잭션 함수) 에서 호출되는 함수가 패키지 수준 변수 ($ 로 시작하는 offset) 또는
+// pointer receiver 의 field 에 쓰는 코드 (str, sti) 탐지 (GLOBAL_STATE_MUTATION)
+// 쓰기는 트랜잭션을 실행한 peer 의 memory 에만 남으므로, 다른 트랜잭션의 호출 경로에서 쓴 값을 읽는 코드는 심각도를 높여 따로 보고
+// (value receiver 의 field 는 호출마다 복사되므로 제외)
+type GSAnalyzer struct {
+	fs            *token.FileSet
+	info          *types.Info
+	decls         map[*types.Func]*ast.FuncDecl
+	reachable     map[*types.Func]*callPath // 진입 함수에서 호출되는 함수와 호출 경로
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+// state ... : 패키지 수준 변수 또는 pointer receiver 의 field
+// key 는 함수 사이에서 같은 상태를 찾기 위한 이름 (receiver 의 field 는 type 이름으로 구분, ex : CC.owner), name 은 코드에서의 이름 (ex : t.owner)
+type state struct {
+	key      string
+	name     string
+	receiver bool
+}
+
+func (s state) String() string {
+	if s.receiver {
+		return "receiver field " + s.name
+	}
+	return "package-level variable " + s.name
+}
+
+// stateAccess ... : 상태에 대한 읽기, 쓰기 (line 은 source code 의 줄)
+type stateAccess struct {
+	state
+	line  int
+	write bool
+}
+
+func (analyzer *GSAnalyzer) Init(fs *token.FileSet, info *types.Info) {
+	analyzer.fs = fs
+	analyzer.info = info
+	analyzer.reachable = make(map[*types.Func]*callPath)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// Findings ...
+func (analyzer *GSAnalyzer) Findings() []Finding {
+	return analyzer.findings
+}
+
+// callees ... : 함수가 호출하는 패키지 안의 함수
+func (analyzer *GSAnalyzer) callees(decl *ast.FuncDecl) map[*types.Func]*ast.CallExpr {
+	res := make(map[*types.Func]*ast.CallExpr)
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if callee := calledFunc(call, analyzer.info); callee != nil && analyzer.decls[callee] != nil && res[callee] == nil {
+				res[callee] = call
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// visit ... : 진입 함수에서 호출되는 함수를 따라가며 처음 도달한 호출 경로를 기록
+func (analyzer *GSAnalyzer) visit(f *types.Func, path *callPath) {
+	if _, ok := analyzer.reachable[f]; ok {
+		return
+	}
+	analyzer.reachable[f] = path
+
+	decl := analyzer.decls[f]
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if callee := calledFunc(call, analyzer.info); callee != nil && analyzer.decls[callee] != nil {
+				analyzer.visit(callee, &callPath{caller: f, call: call})
+			}
+		}
+		return true
+	})
+}
+
+// reaches ... : from 에서 to 가 (간접적으로) 호출되는지 여부
+func (analyzer *GSAnalyzer) reaches(from *types.Func, to *types.Func, visited map[*types.Func]bool) bool {
+	if from == to {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+	for callee := range analyzer.callees(analyzer.decls[from]) {
+		if analyzer.reaches(callee, to, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePath ... : 두 함수가 한 트랜잭션의 호출 경로에 함께 있을 수 있는지 여부 (한 함수가 다른 함수를 호출)
+func (analyzer *GSAnalyzer) samePath(f *types.Func, g *types.Func) bool {
+	return analyzer.reaches(f, g, make(map[*types.Func]bool)) || analyzer.reaches(g, f, make(map[*types.Func]bool))
+}
+
+// globalVar ... : SIL 의 offset 이 패키지 수준 변수 ($name) 이면 그 변수 (type 이름 ($CC) 등은 제외)
+func (analyzer *GSAnalyzer) globalVar(f *types.Func, code icg.CodeInfo) (*types.Var, bool) {
+	op, ok := code.(*icg.StackOpcode)
+	if !ok || op.Params().Len() < 2 {
+		return nil, false
+	}
+	offset := fmt.Sprint(op.Params().Front().Next().Value)
+	if len(offset) < 2 || offset[0] != '$' {
+		return nil, false
+	}
+	v, ok := f.Pkg().Scope().Lookup(offset[1:]).(*types.Var)
+	return v, ok
+}
+
+// fieldWidth ... : struct 의 field 가 차지하는 크기 (symbolTable 에서 field 의 위치를 계산하는 방식과 같음)
+func fieldWidth(t types.Type) int {
+	switch x := t.(type) {
+	case *types.Named:
+		return fieldWidth(x.Underlying())
+	case *types.Struct:
+		width := 0
+		for i := 0; i < x.NumFields(); i++ {
+			width += fieldWidth(x.Field(i).Type())
+		}
+		return width
+	case *types.Basic:
+		return symbolTable.TypeToByte(x.Kind())
+	}
+	return symbolTable.TypeToByte(types.Uintptr)
+}
+
+// fieldName ... : pointer 가 가리키는 struct 에서 location 위치의 field 이름
+func fieldName(t types.Type, location int) (string, bool) {
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return "", false
+	}
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+	if !ok {
+		return "", false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if location == 0 {
+			return st.Field(i).Name(), true
+		}
+		location -= fieldWidth(st.Field(i).Type())
+	}
+	return "", false
+}
+
+// fieldState ... : lod, ldi.t, pop2, ldc, add 로 계산한 field 주소가 pointer receiver 또는 패키지 수준 변수의 field 이면 그 상태
+func (analyzer *GSAnalyzer) fieldState(f *types.Func, decl *ast.FuncDecl, lod *icg.StackOpcode, ldc icg.CodeInfo) state {
+	var res state
+	var base *types.Var
+	if v, ok := analyzer.globalVar(f, lod); ok {
+		base = v
+		res.key = v.Name()
+	} else if lod.IsReceiver() && decl.Recv != nil && len(decl.Recv.List[0].Names) > 0 {
+		base, _ = analyzer.info.Defs[decl.Recv.List[0].Names[0]].(*types.Var)
+		if base != nil {
+			res.key = types.TypeString(base.Type(), types.RelativeTo(f.Pkg()))
+			res.receiver = true
+		}
+	}
+	if base == nil {
+		return res
+	}
+	res.name = base.Name()
+	location := -1
+	if op, ok := ldc.(*icg.StackOpcode); ok && ldc.Opcode() == icg.Ldc {
+		location, _ = strconv.Atoi(fmt.Sprint(op.Params().Front().Value))
+	}
+	if field, ok := fieldName(base.Type(), location); ok {
+		res.key += "." + field
+		res.name += "." + field
+	}
+	return res
+}
+
+// accesses ... : 함수의 SIL 에서 패키지 수준 변수 (str, lod, lda 와 sti, ldi) 와
+// pointer receiver 의 field (lod, ldi.t, pop2, ldc, add 로 계산한 주소에 대한 sti, ldi) 를 읽고 쓰는 코드
+func (analyzer *GSAnalyzer) accesses(f *types.Func, decl *ast.FuncDecl, codeList []icg.CodeInfo) []stateAccess {
+	var res []stateAccess
+	var address []state // lda 또는 field 주소 계산으로 stack 에 있는 주소가 가리키는 상태 (key 가 "" 이면 지역 변수)
+	pop := func() state {
+		if len(address) == 0 {
+			return state{}
+		}
+		s := address[len(address)-1]
+		address = address[:len(address)-1]
+		return s
+	}
+
+	for i := 0; i < len(codeList); i++ {
+		code := codeList[i]
+		isStructLoad := i+1 < len(codeList) && codeList[i+1].Opcode() == icg.Pop2
+		switch code.Opcode() {
+		case icg.Str:
+			if v, ok := analyzer.globalVar(f, code); ok {
+				res = append(res, stateAccess{state{v.Name(), v.Name(), false}, code.GetSourceLine(), true})
+			}
+		case icg.Lod:
+			lod, _ := code.(*icg.StackOpcode)
+			if i+4 < len(codeList) && codeList[i+1].Opcode() == icg.Ldi && codeList[i+2].Opcode() == icg.Pop2 {
+				address = append(address, analyzer.fieldState(f, decl, lod, codeList[i+3]))
+				i += 2
+				continue
+			}
+			if v, ok := analyzer.globalVar(f, code); ok {
+				res = append(res, stateAccess{state{v.Name(), v.Name(), false}, code.GetSourceLine(), false})
+			}
+		case icg.Lda:
+			// 문자열 literal (lda.p @n), &x 는 sti, ldi 의 주소가 아닌 값
+			if lda, _ := code.(*icg.StackOpcode); lda.Type() != icg.Nt || lda.IsAlias() {
+				break
+			}
+			var s state
+			if v, ok := analyzer.globalVar(f, code); ok {
+				s = state{v.Name(), v.Name(), false}
+			}
+			address = append(address, s)
+		case icg.Ldi:
+			if isStructLoad {
+				break // field 주소를 계산하기 위한 struct 의 load
+			}
+			if s := pop(); s.key != "" {
+				res = append(res, stateAccess{s, code.GetSourceLine(), false})
+			}
+		case icg.Sti:
+			if s := pop(); s.key != "" {
+				res = append(res, stateAccess{s, code.GetSourceLine(), true})
+			}
+		}
+	}
+	return res
+}
+
+func (analyzer *GSAnalyzer) report(f *types.Func, decl *ast.FuncDecl, access stateAccess, message string) *Finding {
+	var ccw CCW = GLOBAL_STATE_MUTATION
+	fileName := analyzer.fs.Position(decl.Pos()).Filename
+	finding := newFinding(ccw, fileName, access.line, 0, message)
+	finding.Function = f.Name()
+
+	// 진입 함수까지의 호출 경로
+	for p := analyzer.reachable[f]; p.caller != nil; p = analyzer.reachable[p.caller] {
+		callPos := analyzer.fs.Position(p.call.Pos())
+		finding.AddRelated(callPos.Filename, callPos.Line, "called from "+p.caller.Name())
+	}
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+	return &analyzer.findings[len(analyzer.findings)-1]
+}
+
+// Analysis ... : 패키지의 모든 파일에서 진입 함수와 진입 함수가 호출하는 함수의 SIL 을 분석
+// 쓰기는 함수마다 상태별로 처음 쓰는 위치를, 읽기는 서로 호출하지 않는 (다른 트랜잭션의 호출 경로에 있는) 함수에서 쓴 상태를 읽는 위치를 보고
+func (analyzer *GSAnalyzer) Analysis(files []*ast.File, silTable *icg.SILTable) int {
+	decls, entries := transactionFuncs(files, analyzer.info)
+	analyzer.decls = decls
+
+	// chaincode 의 Init 도 트랜잭션 (instantiate, upgrade) 으로 실행됨
+	for _, decl := range decls {
+		if decl.Recv != nil && decl.Name.Name == "Init" {
+			entries = append(entries, decl)
+		}
+	}
+	for _, entry := range entries {
+		if f, ok := analyzer.info.Defs[entry.Name].(*types.Func); ok {
+			analyzer.visit(f, &callPath{})
+		}
+	}
+
+	// SIL 의 함수는 선언된 파일과 이름으로 찾음
+	codeTable := make(map[string][]icg.CodeInfo)
+	for k, codeList := range silTable.FunctionCodeTable() {
+		codeTable[silTable.FunctionFile(k)+":"+silTable.StringPool().LookupSymbolName(k)] = codeList
+	}
+
+	type funcAccess struct {
+		f        *types.Func
+		decl     *ast.FuncDecl
+		accesses []stateAccess
+	}
+	var list []funcAccess
+	writers := make(map[string][]funcAccess) // 상태를 쓰는 함수
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			f, ok := analyzer.info.Defs[funcDecl.Name].(*types.Func)
+			if !ok || analyzer.reachable[f] == nil {
+				continue
+			}
+			codeList := codeTable[analyzer.fs.Position(funcDecl.Pos()).Filename+":"+funcDecl.Name.Name]
+			access := funcAccess{f, funcDecl, analyzer.accesses(f, funcDecl, codeList)}
+			list = append(list, access)
+
+			written := make(map[string]bool)
+			for _, a := range access.accesses {
+				if a.write && !written[a.key] {
+					written[a.key] = true
+					writers[a.key] = append(writers[a.key], access)
+				}
+			}
+		}
+	}
+
+	// 선언 순서대로 보고
+	for _, fa := range list {
+		reported := make(map[string]bool)
+		for _, a := range fa.accesses {
+			key := fmt.Sprint(a.key, a.write)
+			if reported[key] {
+				continue
+			}
+			if a.write {
+				reported[key] = true
+				message := fmt.Sprintf("%s writes %s, which is kept only in the memory of the peer and is not part of the ledger", fa.f.Name(), a.state)
+				analyzer.report(fa.f, fa.decl, a, message)
+				continue
+			}
+			for _, w := range writers[a.key] {
+				if analyzer.samePath(fa.f, w.f) {
+					continue
+				}
+				reported[key] = true
+				message := fmt.Sprintf("%s reads %s written by %s in another transaction, so endorsing peers may read different values", fa.f.Name(), a.state, w.f.Name())
+				finding := analyzer.report(fa.f, fa.decl, a, message)
+				finding.Severity = High
+				for _, wa := range w.accesses {
+					if wa.write && wa.key == a.key {
+						finding.AddRelated(analyzer.fs.Position(w.decl.Pos()).Filename, wa.line, "written by "+w.f.Name())
+						break
+					}
+				}
+				break
+			}
+		}
+	}
+	return analyzer.analysisCount
+}
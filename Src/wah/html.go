@@ -0,0 +1,277 @@
+package wah
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// htmlContextLines ... : HTML 보고서에서 탐지 위치 앞뒤로 보여주는 소스 코드 라인 수
+const htmlContextLines = 3
+
+type htmlSourceLine struct {
+	Number    int
+	Text      string
+	IsFinding bool
+}
+
+type htmlRelated struct {
+	Location
+	Source string
+}
+
+type htmlFinding struct {
+	Finding
+	Anchor      string
+	Description string
+	Source      []htmlSourceLine
+	Related     []htmlRelated
+}
+
+type htmlCCWSummary struct {
+	ID          string
+	Name        string
+	Severity    Severity
+	Description string
+	Count       int
+}
+
+type htmlFuncSummary struct {
+	File     string
+	Function string
+	Severity Severity
+	Count    int
+}
+
+type htmlReport struct {
+	Report
+	Total      int
+	ByCCW      []htmlCCWSummary
+	ByFunc     []htmlFuncSummary
+	Findings   []htmlFinding
+	Severity   map[Severity]int
+	Severities []Severity
+}
+
+// sourceContext ... : line 을 중심으로 앞뒤 context 라인의 소스 코드
+func sourceContext(lines []string, line int, context int) []htmlSourceLine {
+	var res []htmlSourceLine
+	for i := line - context; i <= line+context; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		res = append(res, htmlSourceLine{Number: i, Text: lines[i-1], IsFinding: i == line})
+	}
+	return res
+}
+
+func newHTMLReport(report Report) htmlReport {
+	fp := new(fingerprinter)
+	fp.Init()
+
+	res := htmlReport{
+		Report:     report,
+		Total:      len(report.Findings),
+		Severity:   make(map[Severity]int),
+		Severities: []Severity{High, Medium, Low},
+	}
+
+	ccwCount := make(map[CCW]int)
+	ccwSeverity := make(map[CCW]Severity)
+	funcIndex := make(map[string]int)
+	for i, f := range report.Findings {
+		hf := htmlFinding{
+			Finding:     f,
+			Anchor:      "finding-" + strconv.Itoa(i+1),
+			Description: f.CCW.Description(),
+			Source:      sourceContext(fp.lines(f.File), f.Line, htmlContextLines),
+		}
+		for _, related := range f.Related {
+			hf.Related = append(hf.Related, htmlRelated{Location: related, Source: strings.TrimSpace(fp.snippetRaw(related.File, related.Line))})
+		}
+		res.Findings = append(res.Findings, hf)
+
+		res.Severity[f.Severity]++
+		ccwCount[f.CCW]++
+		ccwSeverity[f.CCW] = f.Severity
+
+		key := f.File + "\x00" + f.Function
+		index, ok := funcIndex[key]
+		if !ok {
+			index = len(res.ByFunc)
+			funcIndex[key] = index
+			res.ByFunc = append(res.ByFunc, htmlFuncSummary{File: f.File, Function: f.Function})
+		}
+		res.ByFunc[index].Count++
+		if f.Severity > res.ByFunc[index].Severity {
+			res.ByFunc[index].Severity = f.Severity
+		}
+	}
+
+	for _, ccw := range CCWList() {
+		severity, ok := ccwSeverity[ccw]
+		if !ok {
+			severity = ccw.DefaultSeverity()
+		}
+		res.ByCCW = append(res.ByCCW, htmlCCWSummary{
+			ID:          ccw.ID(),
+			Name:        ccw.String(),
+			Severity:    severity,
+			Description: ccw.Description(),
+			Count:       ccwCount[ccw],
+		})
+	}
+
+	sort.SliceStable(res.ByFunc, func(i, j int) bool {
+		return res.ByFunc[i].Count > res.ByFunc[j].Count
+	})
+	return res
+}
+
+// snippetRaw ... : file 의 line 번째 라인 (정규화하지 않음)
+func (fp *fingerprinter) snippetRaw(file string, line int) string {
+	lines := fp.lines(file)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
+
+// WriteHTML ... : 탐지된 보안약점을 하나의 HTML 파일 (외부 리소스 없음) 로 출력
+// 보안약점마다 주변 소스 코드, CCW 설명, 분석기가 사용한 근거 (관련 위치, SMT formula) 를 포함하며
+// CCW 별, 함수 별 요약 표를 함께 출력
+func WriteHTML(w io.Writer, report Report) error {
+	return htmlTemplate.Execute(w, newHTMLReport(report))
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>WAH chaincode weakness report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 { font-size: 1.6em; }
+h2 { border-bottom: 1px solid #e1e4e8; padding-bottom: .3em; margin-top: 2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #e1e4e8; padding: .35em .7em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.count { text-align: right; }
+tr.zero { color: #959da5; }
+.severity { display: inline-block; padding: 0 .5em; border-radius: 3px; font-size: .85em; font-weight: bold; color: #fff; }
+.severity-high { background: #cb2431; }
+.severity-medium { background: #e36209; }
+.severity-low { background: #0366d6; }
+.finding { border: 1px solid #e1e4e8; border-radius: 6px; padding: 0 1em 1em; margin: 1.5em 0; }
+.finding h3 { margin-bottom: .3em; }
+.meta { color: #586069; }
+.description { font-style: italic; }
+pre { background: #f6f8fa; padding: .6em; overflow-x: auto; font-size: .9em; }
+.source { padding: 0; }
+.source span { display: block; padding: 0 .6em; }
+.source .hit { background: #fff5b1; }
+.lineno { display: inline-block; width: 3.5em; color: #959da5; user-select: none; }
+</style>
+</head>
+<body>
+<h1>WAH chaincode weakness report</h1>
+<p>Total weakness count : <strong>{{.Total}}</strong>
+{{- range .Severities}} &middot; <span class="severity severity-{{.}}">{{.}}</span> {{index $.Severity .}}{{end}}
+{{- if .Suppressed}} &middot; suppressed : {{len .Suppressed}}{{end}}
+{{- if .Baselined}} &middot; in baseline : {{len .Baselined}}{{end}}</p>
+
+<h2>Summary by CCW</h2>
+<table>
+<tr><th>ID</th><th>Name</th><th>Severity</th><th>Count</th><th>Description</th></tr>
+{{- range .ByCCW}}
+<tr{{if eq .Count 0}} class="zero"{{end}}><td>{{.ID}}</td><td>{{.Name}}</td><td><span class="severity severity-{{.Severity}}">{{.Severity}}</span></td><td class="count">{{.Count}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Summary by function</h2>
+{{- if .ByFunc}}
+<table>
+<tr><th>File</th><th>Function</th><th>Highest severity</th><th>Count</th></tr>
+{{- range .ByFunc}}
+<tr><td>{{.File}}</td><td>{{if .Function}}{{.Function}}{{else}}(package level){{end}}</td><td><span class="severity severity-{{.Severity}}">{{.Severity}}</span></td><td class="count">{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No weakness detected.</p>
+{{- end}}
+
+{{- if .Findings}}
+<h2>Weaknesses</h2>
+{{- range .Findings}}
+<div class="finding" id="{{.Anchor}}">
+<h3>{{.ID}} : {{.Name}} <span class="severity severity-{{.Severity}}">{{.Severity}}</span></h3>
+<div class="meta">{{.File}} : {{.Line}}{{if .Function}} &middot; function {{.Function}}{{end}}</div>
+{{- if .Message}}
+<p>{{.Message}}</p>
+{{- end}}
+<p class="description">{{.Description}}</p>
+{{- if .Source}}
+<pre class="source">{{range .Source}}<span{{if .IsFinding}} class="hit"{{end}}><span class="lineno">{{.Number}}</span>{{.Text}}</span>{{end}}</pre>
+{{- end}}
+{{- if .Related}}
+<p>Evidence :</p>
+<table>
+<tr><th>Location</th><th>Detail</th><th>Source</th></tr>
+{{- range .Related}}
+<tr><td>{{.File}} : {{.Line}}</td><td>{{.Message}}</td><td><code>{{.Source}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Evidence}}
+<p>SMT formula :</p>
+<pre>{{.Evidence}}</pre>
+{{- end}}
+</div>
+{{- end}}
+{{- end}}
+
+{{- if .Suppressed}}
+<h2>Suppressed weaknesses</h2>
+<table>
+<tr><th>ID</th><th>Name</th><th>Location</th><th>Function</th><th>Reason</th></tr>
+{{- range .Suppressed}}
+<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.File}} : {{.Line}}</td><td>{{.Function}}</td><td>{{.Justification}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Stale}}
+<h2>Stale suppressions</h2>
+<table>
+<tr><th>Location</th><th>IDs</th><th>Reason</th></tr>
+{{- range .Stale}}
+<tr><td>{{.File}} : {{.Line}}</td><td>{{range $i, $id := .IDs}}{{if $i}}, {{end}}{{$id}}{{end}}</td><td>{{.Reason}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Fixed}}
+<h2>Fixed baseline weaknesses</h2>
+<table>
+<tr><th>ID</th><th>Name</th><th>Location</th><th>Function</th><th>Source</th></tr>
+{{- range .Fixed}}
+<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.File}} : {{.Line}}</td><td>{{.Function}}</td><td><code>{{.Snippet}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Errors}}
+<h2>Analysis errors</h2>
+<p>The results below are incomplete for these files or functions.</p>
+<table>
+<tr><th>File</th><th>Function</th><th>Error</th></tr>
+{{- range .Errors}}
+<tr><td>{{.File}}</td><td>{{.Function}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,739 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// IAAnalyzer ...
+// ledger 에서 읽거나 (GetState) 인자로 받은 정수를 overflow, 음수, 0 으로 나누기 검사 없이 계산하여 PutState 로 쓰는 코드 탐지 (UNCHECKED_ARITHMETIC)
+// 피연산자를 DUChain 으로 따라가 definition 마다 SMT 변수를 만들고, 실행 경로의 분기 조건과 함께 z3 로 검사
+type IAAnalyzer struct {
+	analysisFile  string
+	fs            *token.FileSet
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	conditions    []*smtBuilder   // 현재 경로의 분기 조건
+	ops           []arithOp       // 현재 경로에서 계산된 외부 입력의 산술 연산
+	results       map[string]bool // formula, z3 결과 (경로가 달라도 같은 formula 는 한 번만 검사)
+	reported      map[int]bool    // 보고한 산술 연산의 SIL 라인
+	solverErr     error           // z3 를 실행할 수 없는 경우 (이후의 검사는 생략)
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+// arithOp ... : 외부 입력을 피연산자로 사용하는 산술 연산 하나
+// def 는 결과를 저장하는 definition 라인 (다른 식에서 바로 사용하면 -1), divisor 는 나누기의 제수
+type arithOp struct {
+	line       int
+	sourceLine int
+	opcode     icg.Opcode
+	result     string
+	divisor    string
+	def        int
+	bounds     intBounds
+	builder    *smtBuilder
+}
+
+// intBounds ... : 정수 타입의 범위
+type intBounds struct {
+	name     string
+	min      string
+	max      string
+	unsigned bool
+}
+
+var intTypeBounds = map[types.BasicKind]intBounds{
+	types.Int:     {"int", "-9223372036854775808", "9223372036854775807", false},
+	types.Int64:   {"int64", "-9223372036854775808", "9223372036854775807", false},
+	types.Int32:   {"int32", "-2147483648", "2147483647", false},
+	types.Int16:   {"int16", "-32768", "32767", false},
+	types.Int8:    {"int8", "-128", "127", false},
+	types.Uint:    {"uint", "0", "18446744073709551615", true},
+	types.Uint64:  {"uint64", "0", "18446744073709551615", true},
+	types.Uintptr: {"uintptr", "0", "18446744073709551615", true},
+	types.Uint32:  {"uint32", "0", "4294967295", true},
+	types.Uint16:  {"uint16", "0", "65535", true},
+	types.Uint8:   {"uint8", "0", "255", true},
+}
+
+// silTypeBounds ... : 매개변수의 SIL 타입에 해당하는 정수 범위 (int, int32 는 i, int64 는 d 로 생성됨)
+var silTypeBounds = map[icg.SilType]types.BasicKind{
+	icg.I: types.Int64, icg.L: types.Int64, icg.D: types.Int64, icg.S: types.Int16, icg.C: types.Int8,
+	icg.Ui: types.Uint64, icg.Ul: types.Uint64, icg.Us: types.Uint16, icg.Uc: types.Uint8,
+}
+
+// smtTerm ... : SIL 코드를 SMT-LIB 식으로 변환한 결과
+type smtTerm struct {
+	expr   string
+	isBool bool
+}
+
+// smtBuilder ... : SMT 변수 선언, 변수의 definition 제약 조건, 식에 사용된 외부 입력
+type smtBuilder struct {
+	decls       map[string]string // 변수, 범위 제약 조건 (없으면 "")
+	constraints []string
+	defined     map[int]bool
+	sources     []inputSource
+	expr        string // 분기 조건
+}
+
+// inputSource ... : 외부 입력 (ledger 를 읽은 값을 변환한 결과, 매개변수)
+type inputSource struct {
+	desc string
+	line int
+}
+
+func newSMTBuilder() *smtBuilder {
+	return &smtBuilder{decls: make(map[string]string), defined: make(map[int]bool)}
+}
+
+func (builder *smtBuilder) declare(name string, bound string) {
+	if old, ok := builder.decls[name]; !ok || old == "" {
+		builder.decls[name] = bound
+	}
+}
+
+func (analyzer *IAAnalyzer) Init(analysisFile string, fs *token.FileSet, chain vfg.DUChain, codeList []icg.CodeInfo) {
+	analyzer.analysisFile = analysisFile
+	analyzer.fs = fs
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.results = make(map[string]bool)
+	analyzer.reported = make(map[int]bool)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// Copy ... : 분기 이전의 분기 조건, 산술 연산 목록으로 되돌리기 위한 복사
+func (analyzer *IAAnalyzer) Copy() *IAAnalyzer {
+	newAnalyzer := new(IAAnalyzer)
+	*newAnalyzer = *analyzer
+	newAnalyzer.conditions = append([]*smtBuilder(nil), analyzer.conditions...)
+	newAnalyzer.ops = append([]arithOp(nil), analyzer.ops...)
+
+	return newAnalyzer
+}
+
+func smtInt(n int64) string {
+	if n < 0 {
+		return fmt.Sprintf("(- %d)", -n)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+func smtBound(v string) string {
+	if strings.HasPrefix(v, "-") {
+		return "(- " + v[1:] + ")"
+	}
+	return v
+}
+
+func (bounds intBounds) constraint(name string) string {
+	return fmt.Sprintf("(and (<= %s %s) (<= %s %s))", smtBound(bounds.min), name, name, smtBound(bounds.max))
+}
+
+// exprStart ... : end 의 코드가 stack 에 남기는 값을 계산하는 식의 시작 index (찾을 수 없으면 -1)
+// 함수 호출 (ldp ... call) 은 하나의 값으로 봄
+func (analyzer *IAAnalyzer) exprStart(end int) int {
+	need := 1
+	for i := end; i >= 0; i-- {
+		code := analyzer.codeList[i]
+		switch code.Opcode() {
+		case icg.Call:
+			depth := 0
+			for ; i >= 0; i-- {
+				if analyzer.codeList[i].Opcode() == icg.Call {
+					depth++
+				} else if analyzer.codeList[i].Opcode() == icg.Ldp {
+					depth--
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			if i < 0 {
+				return -1
+			}
+			need--
+		case icg.Str, icg.Sti, icg.Label, icg.Fjp, icg.Tjp, icg.Ujp, icg.Proc, icg.Ret, icg.Retv, icg.Ldp:
+			return -1
+		default:
+			need += code.GetPopParameterNum() - code.GetPushParameterNum()
+		}
+		if need == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// callEnd ... : ldp 에 대응하는 call 의 index
+func (analyzer *IAAnalyzer) callEnd(ldp int) int {
+	depth := 0
+	for i := ldp; i < len(analyzer.codeList); i++ {
+		switch analyzer.codeList[i].Opcode() {
+		case icg.Ldp:
+			depth++
+		case icg.Call:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var smtOperators = map[icg.Opcode]string{
+	icg.Add: "+", icg.Sub: "-", icg.Mul: "*", icg.Div: "div", icg.Mod: "mod",
+	icg.Ge: ">=", icg.Gt: ">", icg.Le: "<=", icg.Lt: "<", icg.Eq: "=", icg.And: "and", icg.Or: "or",
+}
+
+// translate ... : codeList[start:end+1] 의 식을 SMT 식으로 변환
+// 변환할 수 없는 값 (함수 호출 결과, 배열 원소 등) 은 범위 제약이 없는 새 변수로 표현
+func (analyzer *IAAnalyzer) translate(builder *smtBuilder, start int, end int) (smtTerm, bool) {
+	var stack []smtTerm
+	pop := func() (smtTerm, bool) {
+		if len(stack) == 0 {
+			return smtTerm{}, false
+		}
+		term := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return term, true
+	}
+	fresh := func(code icg.CodeInfo) smtTerm {
+		name := fmt.Sprintf("u%d", code.GetLine())
+		builder.declare(name, "")
+		return smtTerm{expr: name}
+	}
+
+	for i := start; i <= end; i++ {
+		code := analyzer.codeList[i]
+		switch op := code.Opcode(); op {
+		case icg.Ldc:
+			ldc := code.(*icg.StackOpcode)
+			n, err := strconv.ParseInt(fmt.Sprint(ldc.Params().Front().Value), 10, 64)
+			if err != nil {
+				stack = append(stack, fresh(code))
+				continue
+			}
+			stack = append(stack, smtTerm{expr: smtInt(n)})
+		case icg.Lod:
+			stack = append(stack, analyzer.variable(builder, code.(*icg.StackOpcode)))
+		case icg.Ldp:
+			call := analyzer.callEnd(i)
+			if call == -1 || call > end {
+				return smtTerm{}, false
+			}
+			stack = append(stack, analyzer.callTerm(builder, i, call))
+			i = call
+		case icg.Add, icg.Sub, icg.Mul, icg.Div, icg.Mod, icg.Ge, icg.Gt, icg.Le, icg.Lt, icg.Eq, icg.Ne, icg.And, icg.Or:
+			y, ok1 := pop()
+			x, ok2 := pop()
+			isLogical := op == icg.And || op == icg.Or
+			if !ok1 || !ok2 || x.isBool != isLogical || y.isBool != isLogical {
+				return smtTerm{}, false
+			}
+			if op == icg.Ne {
+				stack = append(stack, smtTerm{expr: fmt.Sprintf("(not (= %s %s))", x.expr, y.expr), isBool: true})
+				continue
+			}
+			isBool := op >= icg.Eq || isLogical
+			stack = append(stack, smtTerm{expr: fmt.Sprintf("(%s %s %s)", smtOperators[op], x.expr, y.expr), isBool: isBool})
+		case icg.Neg, icg.Not:
+			x, ok := pop()
+			if !ok || x.isBool != (op == icg.Not) {
+				return smtTerm{}, false
+			}
+			if op == icg.Neg {
+				stack = append(stack, smtTerm{expr: fmt.Sprintf("(- %s)", x.expr)})
+			} else {
+				stack = append(stack, smtTerm{expr: fmt.Sprintf("(not %s)", x.expr), isBool: true})
+			}
+		case icg.Cvc, icg.Cvs, icg.Cvi, icg.Cvui, icg.Cvl, icg.Cvul:
+			// 정수 사이의 변환은 값을 유지하는 것으로 봄
+		default:
+			for n := 0; n < code.GetPopParameterNum(); n++ {
+				if _, ok := pop(); !ok {
+					return smtTerm{}, false
+				}
+			}
+			for n := 0; n < code.GetPushParameterNum(); n++ {
+				stack = append(stack, fresh(code))
+			}
+		}
+	}
+	if len(stack) != 1 {
+		return smtTerm{}, false
+	}
+	return stack[0], true
+}
+
+// integerConversions ... : SIL 에서 함수 호출로 생성되는 정수 타입 변환
+var integerConversions = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// callTerm ... : codeList[ldp:call+1] 의 함수 호출 결과
+// 정수 타입 변환은 인자의 값, len 은 0 이상의 값, 그 외는 새 변수
+func (analyzer *IAAnalyzer) callTerm(builder *smtBuilder, ldp int, call int) smtTerm {
+	name := fmt.Sprint(analyzer.codeList[call].(*icg.ControlOpcode).Params().Front().Value)
+	if integerConversions[name] && ldp+1 <= call-1 && analyzer.exprStart(call-1) == ldp+1 {
+		if term, ok := analyzer.translate(builder, ldp+1, call-1); ok && !term.isBool {
+			return term
+		}
+	}
+
+	sym := fmt.Sprintf("c%d", analyzer.codeList[call].GetLine())
+	if name == "len" || name == "cap" {
+		builder.declare(sym, fmt.Sprintf("(>= %s 0)", sym))
+	} else {
+		builder.declare(sym, "")
+	}
+	return smtTerm{expr: sym}
+}
+
+// variable ... : lod 한 변수의 reaching definition 에 해당하는 SMT 변수
+func (analyzer *IAAnalyzer) variable(builder *smtBuilder, lod *icg.StackOpcode) smtTerm {
+	offset := fmt.Sprint(lod.Params().Front().Next().Value)
+	def, ok := reachingDefinition(offset, lod, analyzer.chain, analyzer.codeList)
+	if !ok {
+		name := "g" + strings.TrimPrefix(offset, "$")
+		builder.declare(name, "")
+		return smtTerm{expr: name}
+	}
+	name := fmt.Sprintf("d%d", def)
+	analyzer.define(builder, def, name)
+	return smtTerm{expr: name}
+}
+
+// isParameter ... : 함수 시작 (proc) 직후에 매개변수를 저장하는 definition 인지 여부
+func (analyzer *IAAnalyzer) isParameter(index int) bool {
+	for i := index; i > 0; i-- {
+		if analyzer.codeList[i].Opcode() != icg.Str {
+			return false
+		}
+	}
+	return len(analyzer.codeList) > 0 && analyzer.codeList[0].Opcode() == icg.Proc
+}
+
+// isExternal ... : definition 이 ledger 읽기, 인자 slice 를 반환하는 함수의 결과 또는 매개변수인지 여부
+func (analyzer *IAAnalyzer) isExternal(def int) bool {
+	index := findSILIndex(analyzer.codeList, def)
+	if index == -1 {
+		return false
+	}
+	if analyzer.isParameter(index) {
+		return true
+	}
+	call := index - 1
+	for call >= 0 && analyzer.codeList[call].Opcode() == icg.Str {
+		call--
+	}
+	if call < 0 || analyzer.codeList[call].Opcode() != icg.Call {
+		return false
+	}
+	funcName := fmt.Sprint(analyzer.codeList[call].(*icg.ControlOpcode).Params().Front().Value)
+	return matchFunc(funcName, analyzer.config.GetState) || matchFunc(funcName, analyzer.config.PrivateDataReads) || matchFunc(funcName, analyzer.config.ArgumentSources)
+}
+
+// define ... : definition 의 값을 SMT 제약 조건으로 추가
+//   - 정수 매개변수 : 타입의 범위 안의 임의의 값 (외부 입력)
+//   - ledger 또는 인자를 변환한 strconv.Atoi 등의 결과 : 타입의 범위 안의 임의의 값 (외부 입력)
+//   - 그 외의 할당 : 할당한 식과 같은 값
+func (analyzer *IAAnalyzer) define(builder *smtBuilder, def int, name string) {
+	if builder.defined[def] {
+		return
+	}
+	builder.defined[def] = true
+	builder.declare(name, "")
+
+	index := findSILIndex(analyzer.codeList, def)
+	if index == -1 {
+		return
+	}
+	str := analyzer.codeList[index].(*icg.StackOpcode)
+
+	if analyzer.isParameter(index) {
+		if kind, ok := silTypeBounds[str.Type()]; ok {
+			builder.decls[name] = intTypeBounds[kind].constraint(name)
+			builder.sources = append(builder.sources, inputSource{desc: "a parameter", line: str.GetSourceLine()})
+		}
+		return
+	}
+
+	// 함수 호출 결과 (call 다음의 str 들 중 첫 번째가 첫 번째 결과)
+	call := index - 1
+	for call >= 0 && analyzer.codeList[call].Opcode() == icg.Str {
+		call--
+	}
+	if call >= 0 && analyzer.codeList[call].Opcode() == icg.Call {
+		callOp := analyzer.codeList[call].(*icg.ControlOpcode)
+		funcName := fmt.Sprint(callOp.Params().Front().Value)
+		if index != call+1 || !matchFunc(funcName, analyzer.config.ArithmeticSources) {
+			return
+		}
+		var args []icg.CodeInfo
+		for _, arg := range CallArguments(callOp, analyzer.codeList) {
+			args = append(args, arg...)
+		}
+		if _, ok := TraceDefinition(args, analyzer.chain, analyzer.codeList, analyzer.isExternal); !ok {
+			return
+		}
+		kind := types.Int64
+		if strings.Contains(funcName, "Uint") {
+			kind = types.Uint64
+		}
+		builder.decls[name] = intTypeBounds[kind].constraint(name)
+		builder.sources = append(builder.sources, inputSource{desc: "the result of " + funcName, line: callOp.GetSourceLine()})
+		return
+	}
+
+	start := analyzer.exprStart(index - 1)
+	if start == -1 {
+		return
+	}
+	if term, ok := analyzer.translate(builder, start, index-1); ok && !term.isBool {
+		builder.constraints = append(builder.constraints, fmt.Sprintf("(= %s %s)", name, term.expr))
+	}
+}
+
+// integerType ... : source line 의 산술 연산 (a + b, a += b) 의 정수 타입 (정수가 아니면 false)
+func integerType(f *ast.File, fs *token.FileSet, info *types.Info, line int, opcode icg.Opcode) (intBounds, bool) {
+	binaryOp := map[icg.Opcode]token.Token{icg.Add: token.ADD, icg.Sub: token.SUB, icg.Mul: token.MUL, icg.Div: token.QUO, icg.Mod: token.REM}[opcode]
+	assignOp := map[icg.Opcode]token.Token{icg.Add: token.ADD_ASSIGN, icg.Sub: token.SUB_ASSIGN, icg.Mul: token.MUL_ASSIGN, icg.Div: token.QUO_ASSIGN, icg.Mod: token.REM_ASSIGN}[opcode]
+	if f == nil || info == nil {
+		return intBounds{}, false
+	}
+
+	var t types.Type
+	ast.Inspect(f, func(node ast.Node) bool {
+		if t != nil || node == nil || fs.Position(node.Pos()).Line > line || fs.Position(node.End()).Line < line {
+			return false
+		}
+		switch x := node.(type) {
+		case *ast.BinaryExpr:
+			if x.Op == binaryOp && fs.Position(x.OpPos).Line == line {
+				t = info.TypeOf(x)
+			}
+		case *ast.AssignStmt:
+			if x.Tok == assignOp && fs.Position(x.TokPos).Line == line {
+				t = info.TypeOf(x.Lhs[0])
+			}
+		}
+		return t == nil
+	})
+	if t == nil {
+		return intBounds{}, false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return intBounds{}, false
+	}
+	bounds, ok := intTypeBounds[basic.Kind()]
+	return bounds, ok
+}
+
+// conditionExpr ... : source line 의 if, for 문의 조건식
+func conditionExpr(f *ast.File, fs *token.FileSet, line int) ast.Expr {
+	var cond ast.Expr
+	ast.Inspect(f, func(node ast.Node) bool {
+		if cond != nil || node == nil {
+			return false
+		}
+		switch x := node.(type) {
+		case *ast.IfStmt:
+			if x.Cond != nil && fs.Position(x.Cond.Pos()).Line == line {
+				cond = x.Cond
+			}
+		case *ast.ForStmt:
+			if x.Cond != nil && fs.Position(x.Cond.Pos()).Line == line {
+				cond = x.Cond
+			}
+		}
+		return cond == nil
+	})
+	return cond
+}
+
+// conditionLeaves ... : && , || 로 연결된 조건식의 각 항 (SIL 에는 && , || 연산이 생성되지 않고 각 항의 값만 stack 에 남음)
+func conditionLeaves(cond ast.Expr) []ast.Expr {
+	switch x := cond.(type) {
+	case *ast.ParenExpr:
+		return conditionLeaves(x.X)
+	case *ast.BinaryExpr:
+		if x.Op == token.LAND || x.Op == token.LOR {
+			return append(conditionLeaves(x.X), conditionLeaves(x.Y)...)
+		}
+	}
+	return []ast.Expr{cond}
+}
+
+// composeCondition ... : 조건식의 && , || 구조에 각 항의 SMT 식을 순서대로 대입
+func composeCondition(cond ast.Expr, leaves []string) (string, []string) {
+	switch x := cond.(type) {
+	case *ast.ParenExpr:
+		return composeCondition(x.X, leaves)
+	case *ast.BinaryExpr:
+		if x.Op == token.LAND || x.Op == token.LOR {
+			left, rest := composeCondition(x.X, leaves)
+			right, rest := composeCondition(x.Y, rest)
+			op := "and"
+			if x.Op == token.LOR {
+				op = "or"
+			}
+			return fmt.Sprintf("(%s %s %s)", op, left, right), rest
+		}
+	}
+	return leaves[0], leaves[1:]
+}
+
+// Branch ... : 분기 block 의 한쪽 경로를 분석하기 전에 호출
+// 분기 조건 (또는 그 부정) 을 현재 경로의 제약 조건으로 추가
+func (analyzer *IAAnalyzer) Branch(f *ast.File, block *cfg.BranchBlock, isUjp bool) {
+	if block.BranchType() == cfg.UnconditionBranch || f == nil {
+		return
+	}
+	jump := findSILIndex(analyzer.codeList, block.CodeList()[0].GetLine())
+	cond := conditionExpr(f, analyzer.fs, block.CodeList()[0].GetSourceLine())
+	if jump < 1 || cond == nil {
+		return
+	}
+
+	// 각 항의 값을 계산하는 식을 뒤에서부터 찾음
+	builder := newSMTBuilder()
+	leaves := conditionLeaves(cond)
+	terms := make([]string, len(leaves))
+	end := jump - 1
+	for i := len(leaves) - 1; i >= 0; i-- {
+		start := analyzer.exprStart(end)
+		if start == -1 {
+			return
+		}
+		term, ok := analyzer.translate(builder, start, end)
+		if !ok || !term.isBool {
+			return
+		}
+		terms[i] = term.expr
+		end = start - 1
+	}
+	expr, _ := composeCondition(cond, terms)
+
+	// fjp 는 조건이 참이면 다음 block (UjpBlock), tjp 는 조건이 거짓이면 다음 block 으로 진행
+	if isTrue := isUjp == (block.BranchType() == cfg.FalseBranch); !isTrue {
+		expr = fmt.Sprintf("(not %s)", expr)
+	}
+	builder.expr = expr
+	analyzer.conditions = append(analyzer.conditions, builder)
+}
+
+// formula ... : 산술 연산, 현재 경로의 분기 조건과 violation 을 만족하는 값이 있는지 검사하는 SMT formula
+func (analyzer *IAAnalyzer) formula(op arithOp, violation string) string {
+	builders := append([]*smtBuilder{op.builder}, analyzer.conditions...)
+
+	decls := make(map[string]string)
+	var names []string
+	for _, builder := range builders {
+		for name, bound := range builder.decls {
+			if old, ok := decls[name]; !ok {
+				names = append(names, name)
+				decls[name] = bound
+			} else if old == "" {
+				decls[name] = bound
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var formula strings.Builder
+	for _, name := range names {
+		formula.WriteString(fmt.Sprintf("(declare-const %s Int)\n", name))
+	}
+	asserted := make(map[string]bool)
+	assert := func(expr string) {
+		if expr != "" && !asserted[expr] {
+			asserted[expr] = true
+			formula.WriteString(fmt.Sprintf("(assert %s)\n", expr))
+		}
+	}
+	for _, name := range names {
+		assert(decls[name])
+	}
+	for _, builder := range builders {
+		for _, constraint := range builder.constraints {
+			assert(constraint)
+		}
+		assert(builder.expr)
+	}
+	assert(violation)
+	formula.WriteString("(check-sat)\n")
+	return formula.String()
+}
+
+func (analyzer *IAAnalyzer) isSat(formula string) bool {
+	if sat, ok := analyzer.results[formula]; ok {
+		return sat
+	}
+	if analyzer.solverErr != nil {
+		return false
+	}
+	sat, err := checkSat(formula)
+	if err != nil {
+		analyzer.solverErr = err
+		return false
+	}
+	analyzer.results[formula] = sat
+	return sat
+}
+
+var arithOpNames = map[icg.Opcode]string{
+	icg.Add: "addition", icg.Sub: "subtraction", icg.Mul: "multiplication", icg.Div: "division", icg.Mod: "remainder",
+}
+
+// check ... : 산술 연산이 overflow, 음수, 0 으로 나누기가 될 수 있는지 z3 로 검사
+func (analyzer *IAAnalyzer) check(op arithOp, putState *icg.ControlOpcode) {
+	type violation struct {
+		expr   string
+		effect string
+	}
+	var violations []violation
+	if op.opcode == icg.Div || op.opcode == icg.Mod {
+		violations = append(violations, violation{fmt.Sprintf("(= %s 0)", op.divisor), "divide by zero"})
+	}
+	overflow := fmt.Sprintf("(not %s)", op.bounds.constraint(op.result))
+	if op.bounds.unsigned {
+		violations = append(violations, violation{overflow, fmt.Sprintf("overflow or underflow %s", op.bounds.name)})
+	} else {
+		violations = append(violations, violation{overflow, fmt.Sprintf("overflow %s", op.bounds.name)})
+		if op.opcode != icg.Div && op.opcode != icg.Mod {
+			violations = append(violations, violation{fmt.Sprintf("(< %s 0)", op.result), "be negative"})
+		}
+	}
+
+	for _, v := range violations {
+		formula := analyzer.formula(op, v.expr)
+		if analyzer.isSat(formula) {
+			analyzer.report(op, v.effect, putState, formula)
+			return
+		}
+	}
+}
+
+func (analyzer *IAAnalyzer) report(op arithOp, effect string, putState *icg.ControlOpcode, formula string) {
+	analyzer.reported[op.line] = true
+
+	var ccw CCW = UNCHECKED_ARITHMETIC
+	funcName := fmt.Sprint(putState.Params().Front().Value)
+	message := fmt.Sprintf("%s on %s may %s before the result is written by %s", arithOpNames[op.opcode], op.builder.sources[0].desc, effect, funcName)
+	finding := newFinding(ccw, analyzer.analysisFile, op.sourceLine, 0, message)
+	for _, source := range op.builder.sources {
+		finding.AddRelated(analyzer.analysisFile, source.line, "input is "+source.desc)
+	}
+	finding.AddRelated(analyzer.analysisFile, putState.GetSourceLine(), "result is written by "+funcName)
+	finding.Evidence = formula
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// operation ... : codeList[index] 의 산술 연산이 외부 입력을 사용하면 추적 목록에 추가
+func (analyzer *IAAnalyzer) operation(f *ast.File, info *types.Info, index int) {
+	code := analyzer.codeList[index].(*icg.ArithmeticOpcode)
+	if analyzer.reported[code.GetLine()] {
+		return
+	}
+	bounds, ok := integerType(f, analyzer.fs, info, code.GetSourceLine(), code.Opcode())
+	if !ok {
+		return
+	}
+
+	right := analyzer.exprStart(index - 1)
+	if right < 1 {
+		return
+	}
+	left := analyzer.exprStart(right - 1)
+	if left == -1 {
+		return
+	}
+	builder := newSMTBuilder()
+	x, ok1 := analyzer.translate(builder, left, right-1)
+	y, ok2 := analyzer.translate(builder, right, index-1)
+	if !ok1 || !ok2 || x.isBool || y.isBool || len(builder.sources) == 0 {
+		return
+	}
+
+	op := arithOp{line: code.GetLine(), sourceLine: code.GetSourceLine(), opcode: code.Opcode(), divisor: y.expr, def: -1, bounds: bounds, builder: builder}
+	op.result = fmt.Sprintf("(%s %s %s)", smtOperators[op.opcode], x.expr, y.expr)
+	if index+1 < len(analyzer.codeList) && analyzer.codeList[index+1].Opcode() == icg.Str {
+		op.def = analyzer.codeList[index+1].GetLine()
+	}
+	for i, prev := range analyzer.ops {
+		if prev.line == op.line {
+			analyzer.ops[i] = op
+			return
+		}
+	}
+	analyzer.ops = append(analyzer.ops, op)
+}
+
+// flowsTo ... : 산술 연산의 결과가 args 에 사용되는지 여부 (인자 식 안의 연산 또는 결과를 저장한 변수를 DUChain 으로 추적)
+func (analyzer *IAAnalyzer) flowsTo(op arithOp, args []icg.CodeInfo) bool {
+	for _, code := range args {
+		if code.GetLine() == op.line {
+			return true
+		}
+	}
+	if op.def == -1 {
+		return false
+	}
+	_, ok := TraceDefinition(args, analyzer.chain, analyzer.codeList, func(def int) bool { return def == op.def })
+	return ok
+}
+
+func (analyzer *IAAnalyzer) IAAnalysis(f *ast.File, info *types.Info, block cfg.CFGBlock) int {
+	switch b := block.(type) {
+	case *cfg.BasicBlock:
+		for _, sil := range b.CodeList() {
+			switch sil.Opcode() {
+			case icg.Add, icg.Sub, icg.Mul, icg.Div, icg.Mod:
+				arith, ok := sil.(*icg.ArithmeticOpcode)
+				if !ok || arith.Type() == icg.P || arith.Type() == icg.F {
+					continue
+				}
+				if index := findSILIndex(analyzer.codeList, sil.GetLine()); index != -1 {
+					analyzer.operation(f, info, index)
+				}
+			}
+		}
+	case *cfg.CallBlock:
+		callOp, ok := b.CodeList()[0].(*icg.ControlOpcode)
+		if !ok || callOp.Opcode() != icg.Call || len(analyzer.ops) == 0 {
+			break
+		}
+		if !matchFunc(fmt.Sprint(callOp.Params().Front().Value), analyzer.config.PutState) {
+			break
+		}
+		var args []icg.CodeInfo
+		for _, arg := range CallArguments(callOp, analyzer.codeList) {
+			args = append(args, arg...)
+		}
+		for _, op := range analyzer.ops {
+			if !analyzer.reported[op.line] && analyzer.flowsTo(op, args) {
+				analyzer.check(op, callOp)
+			}
+		}
+	}
+	return analyzer.analysisCount
+}
@@ -0,0 +1,121 @@
+package wah
+
+import (
+	"fmt"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+	"WAH_prototype_go-master/Src/icg/symbolTable"
+)
+
+// ICAnalyzer ...
+// InvokeChaincode 로 다른 체인코드를 호출하는 코드 탐지 (CROSS_CHAINCODE_INVOCATION)
+// channel 인자가 literal 이면 같은 channel 과 다른 channel 을 구분하고, 호출 결과가 PutState 로 기록되면 심각도를 높임
+type ICAnalyzer struct {
+	analysisFile  string
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	litTable      *symbolTable.LiteralTable
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+func (analyzer *ICAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo, litTable *symbolTable.LiteralTable) {
+	analyzer.analysisFile = analysisFile
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.litTable = litTable
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+func (analyzer *ICAnalyzer) ICAnalysis(block cfg.CFGBlock) int {
+	switch b := block.(type) {
+	case *cfg.CallBlock:
+		opcode := b.CodeList()[0]
+		if callOp, ok := opcode.(*icg.ControlOpcode); ok && callOp.Opcode() == icg.Call {
+			funcName := fmt.Sprint(callOp.Params().Front().Value)
+			if matchFunc(funcName, analyzer.config.InvokeChaincode) {
+				analyzer.report(callOp)
+			}
+		}
+	}
+	return analyzer.analysisCount
+}
+
+// report ... : InvokeChaincode(chaincodeName, args, channel) 호출 하나에 대한 보안약점 기록
+func (analyzer *ICAnalyzer) report(call *icg.ControlOpcode) {
+	target := "another chaincode"
+	channel, isLiteralChannel := "", false
+	if args := CallArguments(call, analyzer.codeList); len(args) == 3 {
+		if name, ok := literalValue(args[0], analyzer.chain, analyzer.codeList, analyzer.litTable); ok {
+			target = fmt.Sprintf("chaincode %q", name)
+		}
+		channel, isLiteralChannel = literalValue(args[2], analyzer.chain, analyzer.codeList, analyzer.litTable)
+	}
+
+	var message string
+	var severity Severity
+	switch {
+	case isLiteralChannel && channel == "":
+		message = fmt.Sprintf("InvokeChaincode calls %s on the same channel", target)
+		severity = Low
+	case isLiteralChannel:
+		message = fmt.Sprintf("InvokeChaincode calls %s on channel %q; its writes are discarded and its reads are not validated", target, channel)
+		severity = Medium
+	default:
+		message = fmt.Sprintf("InvokeChaincode calls %s on a channel that cannot be resolved statically", target)
+		severity = Medium
+	}
+
+	var ccw CCW = CROSS_CHAINCODE_INVOCATION
+	finding := newFinding(ccw, analyzer.analysisFile, call.GetSourceLine(), 0, message)
+	finding.Severity = severity
+
+	// 호출 결과 (peer.Response) 가 ledger 에 기록되면 검증되지 않은 값이 world state 에 남음
+	if putState, path, ok := analyzer.findLedgerWrite(call); ok {
+		finding.Message += "; the response is written to the ledger"
+		if finding.Severity < High {
+			finding.Severity++
+		}
+		// path 는 PutState 에서 호출 결과 방향이므로 역순으로 기록 (호출 결과 자체는 제외)
+		for i := len(path) - 2; i >= 0; i-- {
+			finding.AddRelated(analyzer.analysisFile, path[i].GetSourceLine(), "response is assigned")
+		}
+		finding.AddRelated(analyzer.analysisFile, putState.GetSourceLine(), "response is written by PutState")
+	}
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// findLedgerWrite ... : InvokeChaincode 의 결과가 value 로 사용된 PutState 호출과 결과에서 PutState 까지의 definition 목록
+func (analyzer *ICAnalyzer) findLedgerWrite(call *icg.ControlOpcode) (icg.CodeInfo, []icg.CodeInfo, bool) {
+	// 결과를 저장하는 변수 (ex : res := stub.InvokeChaincode(...))
+	results := CallResults(call, analyzer.codeList)
+	if len(results) == 0 {
+		return nil, nil, false
+	}
+	response := results[0].GetLine()
+
+	for _, code := range analyzer.codeList[findSILIndex(analyzer.codeList, call.GetLine())+1:] {
+		putState, ok := code.(*icg.ControlOpcode)
+		if !ok || putState.Opcode() != icg.Call || !matchFunc(fmt.Sprint(putState.Params().Front().Value), analyzer.config.PutState) {
+			continue
+		}
+
+		args := CallArguments(putState, analyzer.codeList)
+		if len(args) != 2 {
+			continue
+		}
+		path, ok := TraceDefinition(args[1], analyzer.chain, analyzer.codeList, func(def int) bool {
+			return def == response
+		})
+		if ok {
+			return putState, path, true
+		}
+	}
+	return nil, nil, false
+}
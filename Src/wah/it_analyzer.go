@@ -0,0 +1,246 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// ITAnalyzer ...
+// GetStateByRange, GetQueryResult 등이 반환한 iterator 를 Close 하지 않고 return 하는 실행 경로 탐지 (UNCLOSED_ITERATOR)
+// iterator 가 다른 함수에 전달되거나 반환되면 (DUChain 에 use 가 있으면) 호출한 쪽에서 Close 하는 것으로 보고 추적하지 않음
+type ITAnalyzer struct {
+	analysisFile  string
+	fs            *token.FileSet
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	iterators     []openIterator // 현재 경로에서 열려 있는 iterator
+	reported      map[int]int    // query 라인, findings 의 index (경로가 달라도 query 하나에 하나만 보고)
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+// openIterator ... : query 호출로 열린 iterator 하나 (errOffset, errDef 는 query 가 반환한 error 를 저장하는 변수)
+type openIterator struct {
+	name      string
+	funcName  string
+	line      int
+	errOffset string
+	errDef    int
+}
+
+func (analyzer *ITAnalyzer) Init(analysisFile string, fs *token.FileSet, chain vfg.DUChain, codeList []icg.CodeInfo) {
+	analyzer.analysisFile = analysisFile
+	analyzer.fs = fs
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.reported = make(map[int]int)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// Copy ... : 분기 이전의 iterator 목록으로 되돌리기 위한 복사
+func (analyzer *ITAnalyzer) Copy() *ITAnalyzer {
+	newAnalyzer := new(ITAnalyzer)
+	*newAnalyzer = *analyzer
+	newAnalyzer.iterators = append([]openIterator(nil), analyzer.iterators...)
+
+	return newAnalyzer
+}
+
+func (analyzer *ITAnalyzer) report(it openIterator, returnLine int) {
+	if index, ok := analyzer.reported[it.line]; ok {
+		finding := &analyzer.findings[index]
+		for _, related := range finding.Related {
+			if related.Line == returnLine {
+				return
+			}
+		}
+		finding.AddRelated(analyzer.analysisFile, returnLine, "returns without closing "+it.name)
+		return
+	}
+
+	var ccw CCW = UNCLOSED_ITERATOR
+	message := fmt.Sprintf("the iterator %q returned by %s is not closed on every path", it.name, it.funcName)
+	finding := newFinding(ccw, analyzer.analysisFile, it.line, 0, message)
+	finding.AddRelated(analyzer.analysisFile, returnLine, "returns without closing "+it.name)
+
+	analyzer.reported[it.line] = len(analyzer.findings)
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// iteratorName ... : query 호출 결과를 저장하는 변수 이름 (ex : it, err := stub.GetStateByRange(...) 의 it)
+func (analyzer *ITAnalyzer) iteratorName(f *ast.File, line int) (string, bool) {
+	name, found := "", false
+	ast.Inspect(f, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || found || analyzer.fs.Position(assign.Pos()).Line != line {
+			return !found
+		}
+		if _, ok := assign.Rhs[0].(*ast.CallExpr); !ok || len(assign.Rhs) != 1 {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+			name, found = ident.Name, true
+		}
+		return false
+	})
+	return name, found
+}
+
+// isClosedByDefer ... : query 를 호출한 함수에 defer name.Close() 또는 defer func() { ... name.Close() ... }() 가 있는지 여부
+// defer 문의 호출은 SIL 로 생성되지 않으므로 AST 로 검사
+func (analyzer *ITAnalyzer) isClosedByDefer(f *ast.File, name string, line int) bool {
+	closed := false
+	isClose := func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Close" {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == name {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		if line < analyzer.fs.Position(funcDecl.Pos()).Line || analyzer.fs.Position(funcDecl.End()).Line < line {
+			continue
+		}
+
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			deferStmt, ok := node.(*ast.DeferStmt)
+			if !ok {
+				return !closed
+			}
+			funcLit, ok := deferStmt.Call.Fun.(*ast.FuncLit)
+			if !ok {
+				closed = closed || isClose(deferStmt.Call)
+				return false
+			}
+			ast.Inspect(funcLit.Body, func(node ast.Node) bool {
+				closed = closed || isClose(node)
+				return !closed
+			})
+			return false
+		})
+	}
+	return closed
+}
+
+// open ... : query 호출 결과 iterator 를 추적 목록에 추가
+func (analyzer *ITAnalyzer) open(f *ast.File, call *icg.ControlOpcode, funcName string) {
+	results := CallResults(call, analyzer.codeList)
+	if len(results) == 0 || f == nil {
+		return
+	}
+
+	// iterator 가 다른 함수의 인자로 전달되거나 반환되는 경우
+	itOffset := fmt.Sprint(results[0].(*icg.StackOpcode).Params().Front().Next().Value)
+	if uses, _ := analyzer.chain.LookUpUseOfDef(itOffset, results[0].GetLine()); len(uses) > 0 {
+		return
+	}
+
+	name, ok := analyzer.iteratorName(f, call.GetSourceLine())
+	if !ok || analyzer.isClosedByDefer(f, name, call.GetSourceLine()) {
+		return
+	}
+
+	it := openIterator{name: name, funcName: funcName, line: call.GetSourceLine(), errDef: -1}
+	// error 는 마지막 반환 값 (WithPagination query 는 iterator, metadata, error 를 반환)
+	if len(results) > 1 {
+		errResult := results[len(results)-1]
+		it.errOffset = fmt.Sprint(errResult.(*icg.StackOpcode).Params().Front().Next().Value)
+		it.errDef = errResult.GetLine()
+	}
+	analyzer.iterators = append(analyzer.iterators, it)
+}
+
+// close ... : name.Close() 호출로 닫힌 iterator 를 추적 목록에서 제거
+func (analyzer *ITAnalyzer) close(funcName string) {
+	var res []openIterator
+	for _, it := range analyzer.iterators {
+		if funcName != it.name+".Close" {
+			res = append(res, it)
+		}
+	}
+	analyzer.iterators = res
+}
+
+// Branch ... : 분기 block 의 한쪽 경로를 분석하기 전에 호출
+// query 가 반환한 error 가 nil 이 아닌 경로에서는 iterator 가 nil 이므로 추적하지 않음
+func (analyzer *ITAnalyzer) Branch(block *cfg.BranchBlock, isUjp bool) {
+	if block.BranchType() == cfg.UnconditionBranch || len(analyzer.iterators) == 0 {
+		return
+	}
+
+	// if err != nil 의 조건 (lod err, ldc 0, ne|eq, fjp|tjp)
+	index := findSILIndex(analyzer.codeList, block.CodeList()[0].GetLine())
+	if index < 3 {
+		return
+	}
+	cmp := analyzer.codeList[index-1]
+	ldc, isLdc := analyzer.codeList[index-2].(*icg.StackOpcode)
+	lod, isLod := analyzer.codeList[index-3].(*icg.StackOpcode)
+	if (cmp.Opcode() != icg.Ne && cmp.Opcode() != icg.Eq) || !isLdc || ldc.Opcode() != icg.Ldc || fmt.Sprint(ldc.Params().Front().Value) != "0" || !isLod || lod.Opcode() != icg.Lod {
+		return
+	}
+
+	// fjp 는 조건이 참이면 다음 block (UjpBlock), tjp 는 조건이 거짓이면 다음 block 으로 진행
+	isErrSide := (cmp.Opcode() == icg.Ne) == (block.BranchType() == cfg.FalseBranch)
+	if isErrSide != isUjp {
+		return
+	}
+
+	offset := fmt.Sprint(lod.Params().Front().Next().Value)
+	def, ok := analyzer.chain.LookUpDefOfUse(offset, lod.GetLine())
+	var res []openIterator
+	for _, it := range analyzer.iterators {
+		if !(ok && offset == it.errOffset && def == it.errDef) {
+			res = append(res, it)
+		}
+	}
+	analyzer.iterators = res
+}
+
+// returnLine ... : ret 명령어에는 소스 라인이 없으므로 반환 값을 만드는 이전 코드의 소스 라인을 사용
+func (analyzer *ITAnalyzer) returnLine(ret icg.CodeInfo) int {
+	for i := findSILIndex(analyzer.codeList, ret.GetLine()); i >= 0; i-- {
+		if line := analyzer.codeList[i].GetSourceLine(); line > 0 {
+			return line
+		}
+	}
+	return 0
+}
+
+func (analyzer *ITAnalyzer) ITAnalysis(f *ast.File, block cfg.CFGBlock) int {
+	switch b := block.(type) {
+	case *cfg.CallBlock:
+		opcode := b.CodeList()[0]
+		callOp, ok := opcode.(*icg.ControlOpcode)
+		if !ok || callOp.Opcode() != icg.Call {
+			break
+		}
+		funcName := fmt.Sprint(callOp.Params().Front().Value)
+		if matchFunc(funcName, analyzer.config.IteratorQueries) {
+			analyzer.open(f, callOp, funcName)
+		} else {
+			analyzer.close(funcName)
+		}
+	case *cfg.ReturnBlock:
+		line := analyzer.returnLine(b.CodeList()[0])
+		for _, it := range analyzer.iterators {
+			analyzer.report(it, line)
+		}
+	}
+	return analyzer.analysisCount
+}
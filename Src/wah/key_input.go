@@ -0,0 +1,455 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// keyInput ... : 검증되지 않은 입력 (pos 는 입력을 읽은 호출 또는 트랜잭션 함수의 매개변수, desc 는 입력의 설명, origin 은 pos 의 설명)
+type keyInput struct {
+	pos    token.Pos
+	desc   string
+	origin string
+}
+
+// sourceInput ... : 입력 함수 (config.ArgumentSources, config.TransientSources) 의 호출로 읽은 입력
+func sourceInput(pos token.Pos, funcName string) keyInput {
+	return keyInput{pos, "input read by " + funcName, "input is read by " + funcName}
+}
+
+// paramInput ... : contract 의 트랜잭션 함수가 client 로부터 전달받는 매개변수
+func paramInput(param *types.Var, funcName string) keyInput {
+	desc := fmt.Sprintf("parameter %s of transaction %s", param.Name(), funcName)
+	return keyInput{param.Pos(), desc, fmt.Sprintf("%s is passed by the client", desc)}
+}
+
+// keyArg ... : ledger key 로 사용되는 인자와 인자의 설명
+type keyArg struct {
+	expr ast.Expr
+	desc string
+}
+
+// keyArguments ... : key 를 만드는 함수 (config.KeySinks) 의 인자 중 key 로 사용되는 인자
+//   - CreateCompositeKey(objectType, attributes)
+//   - GetStateByRange(startKey, endKey, ...)
+//   - PutPrivateData(collection, key, value), DelPrivateData(collection, key)
+//   - PutState(key, value), DelState(key)
+func keyArguments(funcName string, args []ast.Expr) []keyArg {
+	switch {
+	case strings.HasSuffix(funcName, "CreateCompositeKey") && len(args) == 2:
+		return []keyArg{{args[0], "object type"}, {args[1], "attributes"}}
+	case strings.Contains(funcName, "ByRange") && len(args) >= 2:
+		return []keyArg{{args[0], "start key"}, {args[1], "end key"}}
+	case strings.HasSuffix(funcName, "PrivateData") && len(args) >= 2:
+		return []keyArg{{args[1], "key"}}
+	case len(args) >= 1:
+		return []keyArg{{args[0], "key"}}
+	}
+	return nil
+}
+
+// keyInputWalker ... : 함수 하나의 문장을 실행 경로를 따라가며 입력에서 만들어진 변수를 추적
+type keyInputWalker struct {
+	analyzer *ASTAnalyzer
+	info     *types.Info
+	decls    map[*types.Func]*ast.FuncDecl
+	params   map[*types.Var]keyInput // 호출하는 함수에서 입력을 전달받는 매개변수
+	tainted  map[types.Object]keyInput
+	report   bool // false 이면 매개변수의 오염 여부만 계산
+	changed  bool
+}
+
+func (walker *keyInputWalker) object(expr ast.Expr) types.Object {
+	ident, ok := unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if obj := walker.info.Uses[ident]; obj != nil {
+		return obj
+	}
+	return walker.info.Defs[ident]
+}
+
+// isKeyType ... : key 를 만드는 데 사용될 수 있는 타입인지 여부 (정수, bool, error 는 제외)
+func isKeyType(t types.Type) bool {
+	if types.Identical(t, types.Universe.Lookup("error").Type()) {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return !ok || basic.Info()&types.IsString != 0
+}
+
+func (walker *keyInputWalker) isLength(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	builtin, ok := walker.info.Uses[ident].(*types.Builtin)
+	return ok && (builtin.Name() == "len" || builtin.Name() == "cap")
+}
+
+func (walker *keyInputWalker) isPanic(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	builtin, ok := walker.info.Uses[ident].(*types.Builtin)
+	return ok && builtin.Name() == "panic"
+}
+
+// input ... : expr 이 검증되지 않은 입력을 포함하는지 여부
+// len(args) 는 입력의 내용이 아니며, key 를 만드는 함수의 결과는 그 호출에서 보고하므로 제외
+func (walker *keyInputWalker) input(expr ast.Expr) (keyInput, bool) {
+	config := walker.analyzer.config
+	var res keyInput
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			funcName := icg.NodeString(walker.analyzer.fs, x.Fun)
+			if matchFunc(funcName, config.ArgumentSources) || matchFunc(funcName, config.TransientSources) {
+				res, found = sourceInput(x.Pos(), funcName), true
+			}
+			if walker.isLength(x) || matchFunc(funcName, config.KeySinks) {
+				return false
+			}
+		case *ast.Ident:
+			if in, ok := walker.tainted[walker.object(x)]; ok {
+				res, found = in, true
+			}
+		}
+		return !found
+	})
+	return res, found
+}
+
+func (walker *keyInputWalker) assign(lhs ast.Expr, rhs ast.Expr, tok token.Token) {
+	obj := walker.object(lhs)
+	if obj == nil {
+		return
+	}
+	if in, ok := walker.input(rhs); ok && isKeyType(obj.Type()) {
+		walker.tainted[obj] = in
+	} else if tok != token.ADD_ASSIGN {
+		delete(walker.tainted, obj)
+	}
+}
+
+// isEmptyCheck ... : 빈 문자열과의 비교 (ex : k == "") 는 입력의 내용을 검증하지 않음
+func isEmptyCheck(expr *ast.BinaryExpr) bool {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return false
+	}
+	for _, operand := range []ast.Expr{expr.X, expr.Y} {
+		if lit, ok := unparen(operand).(*ast.BasicLit); ok && lit.Kind == token.STRING && (lit.Value == `""` || lit.Value == "``") {
+			return true
+		}
+	}
+	return false
+}
+
+// validate ... : if, switch 의 조건 또는 검증 함수의 인자에서 사용된 변수를 검증된 것으로 봄 (len, 빈 문자열과의 비교 제외)
+func (walker *keyInputWalker) validate(node ast.Node) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.CallExpr:
+			return !walker.isLength(x)
+		case *ast.BinaryExpr:
+			return !isEmptyCheck(x)
+		case *ast.Ident:
+			delete(walker.tainted, walker.object(x))
+		}
+		return true
+	})
+}
+
+// call ... : 패키지 안의 함수에 입력을 전달하면 매개변수를 오염된 것으로 기록
+func (walker *keyInputWalker) call(call *ast.CallExpr) {
+	decl, ok := walker.decls[calledFunc(call, walker.info)]
+	if !ok {
+		return
+	}
+	var params []*types.Var
+	for _, field := range decl.Type.Params.List {
+		for _, name := range field.Names {
+			if param, ok := walker.info.Defs[name].(*types.Var); ok {
+				params = append(params, param)
+			}
+		}
+	}
+	for i, arg := range call.Args {
+		if i >= len(params) {
+			break
+		}
+		if _, ok := walker.params[params[i]]; ok || !isKeyType(params[i].Type()) {
+			continue
+		}
+		if in, ok := walker.input(arg); ok {
+			walker.params[params[i]] = in
+			walker.changed = true
+		}
+	}
+}
+
+func (walker *keyInputWalker) walk(funcDecl *ast.FuncDecl) {
+	walker.tainted = make(map[types.Object]keyInput)
+	for param, in := range walker.params {
+		walker.tainted[param] = in
+	}
+	walker.stmts(funcDecl.Body.List)
+}
+
+// snapshot ... : 분기의 한쪽 경로를 분석하기 전의 오염된 변수 목록
+func (walker *keyInputWalker) snapshot() map[types.Object]keyInput {
+	res := make(map[types.Object]keyInput, len(walker.tainted))
+	for obj, in := range walker.tainted {
+		res[obj] = in
+	}
+	return res
+}
+
+// merge ... : 분기가 합쳐지는 곳에서는 한 경로라도 오염된 변수를 오염된 것으로 봄 (종료하는 경로는 states 에서 제외)
+func (walker *keyInputWalker) merge(states []map[types.Object]keyInput) {
+	walker.tainted = make(map[types.Object]keyInput)
+	for _, state := range states {
+		for obj, in := range state {
+			if _, ok := walker.tainted[obj]; !ok {
+				walker.tainted[obj] = in
+			}
+		}
+	}
+}
+
+// stmts ... : 문장 목록을 순서대로 분석하고 종료 (return, panic, continue, goto) 하는 경로인지 반환
+func (walker *keyInputWalker) stmts(list []ast.Stmt) bool {
+	for _, stmt := range list {
+		if walker.stmt(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// stmt ... : if, switch, 반복문은 경로마다 오염된 변수 목록을 복사해 분석한 뒤 합침
+// 한쪽 경로에서만 검증한 변수는 합친 뒤에도 오염된 변수로 남음
+func (walker *keyInputWalker) stmt(stmt ast.Stmt) bool {
+	switch x := stmt.(type) {
+	case nil:
+		return false
+	case *ast.BlockStmt:
+		return walker.stmts(x.List)
+	case *ast.LabeledStmt:
+		return walker.stmt(x.Stmt)
+	case *ast.IfStmt:
+		walker.stmt(x.Init)
+		walker.node(x.Cond)
+		walker.validate(x.Cond)
+		before := walker.snapshot()
+		var states []map[types.Object]keyInput
+		if !walker.stmt(x.Body) {
+			states = append(states, walker.tainted)
+		}
+		walker.tainted = before
+		if !walker.stmt(x.Else) {
+			states = append(states, walker.tainted)
+		}
+		walker.merge(states)
+		return len(states) == 0
+	case *ast.SwitchStmt:
+		walker.stmt(x.Init)
+		walker.node(x.Tag)
+		walker.validate(x.Tag)
+		return walker.clauses(x.Body)
+	case *ast.TypeSwitchStmt:
+		walker.stmt(x.Init)
+		walker.stmt(x.Assign)
+		return walker.clauses(x.Body)
+	case *ast.SelectStmt:
+		return walker.clauses(x.Body)
+	case *ast.ForStmt:
+		walker.stmt(x.Init)
+		walker.node(x.Cond)
+		before := walker.snapshot()
+		walker.stmt(x.Body)
+		walker.stmt(x.Post)
+		walker.merge([]map[types.Object]keyInput{before, walker.tainted})
+		return false
+	case *ast.RangeStmt:
+		walker.node(x.X)
+		if x.Key != nil {
+			walker.assign(x.Key, x.X, token.DEFINE)
+		}
+		if x.Value != nil {
+			walker.assign(x.Value, x.X, token.DEFINE)
+		}
+		before := walker.snapshot()
+		walker.stmt(x.Body)
+		walker.merge([]map[types.Object]keyInput{before, walker.tainted})
+		return false
+	case *ast.ReturnStmt:
+		walker.node(x)
+		return true
+	case *ast.BranchStmt:
+		return x.Tok == token.CONTINUE || x.Tok == token.GOTO
+	case *ast.ExprStmt:
+		walker.node(x)
+		return walker.isPanic(x.X)
+	}
+	walker.node(stmt)
+	return false
+}
+
+// clauses ... : switch, select 의 case 마다 분석한 뒤 합침 (default 가 없으면 어느 case 도 실행되지 않는 경로 포함)
+func (walker *keyInputWalker) clauses(body *ast.BlockStmt) bool {
+	before := walker.snapshot()
+	var states []map[types.Object]keyInput
+	hasDefault := false
+	for _, clause := range body.List {
+		walker.tainted = make(map[types.Object]keyInput, len(before))
+		for obj, in := range before {
+			walker.tainted[obj] = in
+		}
+		var list []ast.Stmt
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			for _, expr := range c.List {
+				walker.node(expr)
+			}
+			hasDefault = hasDefault || c.List == nil
+			list = c.Body
+		case *ast.CommClause:
+			walker.stmt(c.Comm)
+			hasDefault = hasDefault || c.Comm == nil
+			list = c.Body
+		}
+		if !walker.stmts(list) {
+			states = append(states, walker.tainted)
+		}
+	}
+	if !hasDefault {
+		states = append(states, before)
+	}
+	walker.merge(states)
+	return len(states) == 0
+}
+
+// node ... : 분기가 없는 문장 또는 식의 할당, 검증 함수의 호출, key 를 만드는 함수의 호출을 분석
+func (walker *keyInputWalker) node(node ast.Node) {
+	if node == nil {
+		return
+	}
+	config := walker.analyzer.config
+
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range x.Lhs {
+				if len(x.Rhs) == len(x.Lhs) {
+					walker.assign(lhs, x.Rhs[i], x.Tok)
+				} else {
+					walker.assign(lhs, x.Rhs[0], x.Tok) // 여러 값을 반환하는 입력 함수의 결과는 모두 입력
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range x.Names {
+				if len(x.Values) == len(x.Names) {
+					walker.assign(name, x.Values[i], token.DEFINE)
+				} else if len(x.Values) == 1 {
+					walker.assign(name, x.Values[0], token.DEFINE)
+				}
+			}
+		case *ast.CallExpr:
+			funcName := icg.NodeString(walker.analyzer.fs, x.Fun)
+			if matchFuncPrefix(funcName, config.KeyValidators) {
+				walker.validate(x)
+				break
+			}
+			walker.call(x)
+			if !walker.report || !matchFunc(funcName, config.KeySinks) {
+				break
+			}
+			for _, arg := range keyArguments(funcName, x.Args) {
+				if in, ok := walker.input(arg.expr); ok {
+					walker.analyzer.reportKeyInput(x, funcName, arg, in)
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (analyzer *ASTAnalyzer) reportKeyInput(call *ast.CallExpr, funcName string, arg keyArg, in keyInput) {
+	var ccw CCW = UNVALIDATED_KEY_INPUT
+	position := analyzer.fs.Position(call.Pos())
+	message := fmt.Sprintf("%s is used as the %s of %s without validation", in.desc, arg.desc, funcName)
+	analyzer.report(ccw, call, position, message)
+
+	finding := &analyzer.findings[len(analyzer.findings)-1]
+	origin := analyzer.fs.Position(in.pos)
+	finding.AddRelated(origin.Filename, origin.Line, in.origin)
+}
+
+// keyInputParams ... : Invoke 등에서 입력 (args) 을 전달받는 매개변수 (패키지 안의 호출을 변화가 없을 때까지 반복 분석)
+// contract 의 트랜잭션 함수의 매개변수 (트랜잭션 context 제외) 는 client 가 전달하는 입력
+func (analyzer *ASTAnalyzer) keyInputParams(info *types.Info, decls map[*types.Func]*ast.FuncDecl) map[*types.Var]keyInput {
+	var list []*ast.FuncDecl
+	for _, decl := range decls {
+		list = append(list, decl)
+	}
+	// 여러 곳에서 호출되는 함수는 먼저 선언된 호출의 입력을 기록하도록 선언 순서대로 분석
+	sort.Slice(list, func(i, j int) bool { return list[i].Pos() < list[j].Pos() })
+
+	walker := &keyInputWalker{analyzer: analyzer, info: info, decls: decls, params: make(map[*types.Var]keyInput)}
+	for f, decl := range decls {
+		if !isContractTransaction(f) {
+			continue
+		}
+		for _, field := range decl.Type.Params.List {
+			for _, name := range field.Names {
+				param, ok := info.Defs[name].(*types.Var)
+				if ok && isKeyType(param.Type()) && !isTransactionContext(param.Type()) {
+					walker.params[param] = paramInput(param, f.Name())
+				}
+			}
+		}
+	}
+	for walker.changed = true; walker.changed; {
+		walker.changed = false
+		for _, decl := range list {
+			walker.walk(decl)
+		}
+	}
+	return walker.params
+}
+
+// KeyInputAnalysis ... : 인자 (GetStringArgs, GetFunctionAndParameters, contract 의 트랜잭션 함수의 매개변수) 또는 transient data 를 검증하지 않고
+// PutState, DelState 의 key, CreateCompositeKey 의 attribute, GetStateByRange 의 범위로 사용하는 코드 탐지 (UNVALIDATED_KEY_INPUT)
+// composite key 구분자 (\x00) 나 다른 entity 의 prefix 를 포함한 key 로 다른 entity 를 덮어쓸 수 있음
+// UNCHECKED_INPUT_ARGUMENTS 와 같은 입력 (config.ArgumentSources) 을 오염원으로 하여 함수 단위로 변수의 할당을 실행 경로를 따라 추적하며 (매개변수로 전달된 입력 포함),
+// if, switch 의 조건에서 비교하거나 (빈 문자열과의 비교 제외) 검증 함수 (config.KeyValidators) 에 전달한 변수는 그 경로에서 검증된 것으로 봄
+// 분기가 합쳐지는 곳에서는 한 경로라도 검증하지 않은 변수를 오염된 것으로 봄 (ex : 다른 분기에서만 검증한 key)
+// (SIL 에는 composite literal ([]string{...}) 이 생성되지 않아 attribute 를 추적할 수 없으므로 AST 를 사용)
+func (analyzer *ASTAnalyzer) KeyInputAnalysis(funcDecl *ast.FuncDecl, info *types.Info, params map[*types.Var]keyInput) {
+	if funcDecl.Body == nil {
+		return
+	}
+	walker := &keyInputWalker{analyzer: analyzer, info: info, params: params, report: true}
+	walker.walk(funcDecl)
+}
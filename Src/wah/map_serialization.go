@@ -0,0 +1,297 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// serializedMap ... : map 을 포함하는 값을 순서가 보장되지 않게 직렬화한 결과 (MAP_SERIALIZATION)
+type serializedMap struct {
+	pos    token.Pos
+	reason string
+}
+
+// mapField ... : t 가 map 을 포함하면 map 을 포함하는 필드 경로 (ex : Wrapper.Items.Owners) 와 map 타입
+// t 자체가 map 이면 경로는 prefix
+func mapField(t types.Type, prefix string, visited map[types.Type]bool) (string, string, bool) {
+	if visited[t] {
+		return "", "", false
+	}
+	visited[t] = true
+
+	switch x := t.(type) {
+	case *types.Map:
+		return prefix, x.String(), true
+	case *types.Named:
+		name := prefix
+		if name == "" {
+			name = x.Obj().Name()
+		}
+		return mapField(x.Underlying(), name, visited)
+	case *types.Pointer:
+		return mapField(x.Elem(), prefix, visited)
+	case *types.Slice:
+		return mapField(x.Elem(), prefix, visited)
+	case *types.Array:
+		return mapField(x.Elem(), prefix, visited)
+	case *types.Struct:
+		for i := 0; i < x.NumFields(); i++ {
+			field := x.Field(i)
+			path := field.Name()
+			if prefix != "" {
+				path = prefix + "." + field.Name()
+			}
+			if path, mapType, ok := mapField(field.Type(), path, visited); ok {
+				return path, mapType, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// mapReason ... : 직렬화한 값에 map 이 포함되는 이유
+func mapReason(t types.Type) (string, bool) {
+	path, mapType, ok := mapField(t, "", make(map[types.Type]bool))
+	if !ok {
+		return "", false
+	}
+	if _, isMap := t.Underlying().(*types.Map); isMap {
+		return fmt.Sprintf("the value is a map (%s)", mapType), true
+	}
+	return fmt.Sprintf("field %s is a map (%s)", path, mapType), true
+}
+
+// calledFunc ... : 호출되는 함수 (타입 정보가 없거나 함수 값을 호출하는 경우 nil)
+func calledFunc(call *ast.CallExpr, info *types.Info) *types.Func {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	f, _ := info.Uses[ident].(*types.Func)
+	return f
+}
+
+// funcDecls ... : 분석 대상 파일에 선언된 함수, 메소드
+func (analyzer *ASTAnalyzer) funcDecls(info *types.Info) map[*types.Func]*ast.FuncDecl {
+	files := analyzer.files
+	if files == nil {
+		files = []*ast.File{analyzer.file}
+	}
+
+	res := make(map[*types.Func]*ast.FuncDecl)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+				if obj, ok := info.Defs[funcDecl.Name].(*types.Func); ok {
+					res[obj] = funcDecl
+				}
+			}
+		}
+	}
+	return res
+}
+
+// iteratesMap ... : 함수가 map 을 range 로 순회하여 []byte 또는 string 을 만드는 helper 인지 검사
+// 순회한 key 를 sort 패키지로 정렬하는 경우는 제외
+func iteratesMap(funcDecl *ast.FuncDecl, info *types.Info) (string, bool) {
+	sig, ok := info.Defs[funcDecl.Name].Type().(*types.Signature)
+	if !ok {
+		return "", false
+	}
+	returnsBytes := false
+	for i := 0; i < sig.Results().Len(); i++ {
+		t := sig.Results().At(i).Type().Underlying()
+		if basic, ok := t.(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+			returnsBytes = true
+		}
+		if slice, ok := t.(*types.Slice); ok && types.Identical(slice.Elem(), types.Typ[types.Byte]) {
+			returnsBytes = true
+		}
+	}
+	if !returnsBytes {
+		return "", false
+	}
+
+	rangeMap, sorted := "", false
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.RangeStmt:
+			if tv, ok := info.Types[x.X]; ok && rangeMap == "" {
+				if _, isMap := tv.Type.Underlying().(*types.Map); isMap {
+					rangeMap = tv.Type.String()
+				}
+			}
+		case *ast.CallExpr:
+			if f := calledFunc(x, info); f != nil && f.Pkg() != nil && (f.Pkg().Path() == "sort" || f.Pkg().Path() == "slices") {
+				sorted = true
+			}
+		}
+		return true
+	})
+	return rangeMap, rangeMap != "" && !sorted
+}
+
+// MapSerializationAnalysis ... : map 을 포함하는 값을 순서가 보장되지 않는 방식으로 직렬화하여
+// PutState, SetEvent, shim.Success 등으로 전달하는 코드 탐지 (함수 단위로 변수의 할당을 소스 순서대로 추적)
+//   - 순서가 보장되지 않는 encoder (config.UnorderedEncoders, ex : encoding/gob, protobuf) 로 map 을 포함하는 값을 직렬화
+//   - map 을 순회하여 []byte, string 을 만드는 helper 함수 호출
+//
+// encoding/json, fmt 는 map 의 key 를 정렬하여 출력하므로 제외
+func (analyzer *ASTAnalyzer) MapSerializationAnalysis(funcDecl *ast.FuncDecl, info *types.Info, decls map[*types.Func]*ast.FuncDecl) {
+	if funcDecl.Body == nil {
+		return
+	}
+	tainted := make(map[types.Object]serializedMap)
+	writers := make(map[types.Object]types.Object) // encoder, encoder 가 쓰는 변수 (ex : enc := gob.NewEncoder(&buf) 의 buf)
+
+	object := func(expr ast.Expr) types.Object {
+		for {
+			switch x := expr.(type) {
+			case *ast.ParenExpr:
+				expr = x.X
+				continue
+			case *ast.UnaryExpr:
+				expr = x.X
+				continue
+			case *ast.Ident:
+				if obj := info.Uses[x]; obj != nil {
+					return obj
+				}
+				return info.Defs[x]
+			}
+			return nil
+		}
+	}
+
+	var serialized func(expr ast.Expr) (serializedMap, bool)
+	serialized = func(expr ast.Expr) (serializedMap, bool) {
+		switch x := expr.(type) {
+		case *ast.ParenExpr:
+			return serialized(x.X)
+		case *ast.UnaryExpr:
+			return serialized(x.X)
+		case *ast.SliceExpr:
+			return serialized(x.X)
+		case *ast.BinaryExpr:
+			if s, ok := serialized(x.X); ok {
+				return s, true
+			}
+			return serialized(x.Y)
+		case *ast.Ident:
+			if obj := object(x); obj != nil {
+				s, ok := tainted[obj]
+				return s, ok
+			}
+		case *ast.CallExpr:
+			// 변환 ([]byte(s)), append, 직렬화된 변수의 메소드 호출 (buf.Bytes())
+			if tv, ok := info.Types[x.Fun]; ok && tv.IsType() && len(x.Args) == 1 {
+				return serialized(x.Args[0])
+			}
+			if ident, ok := x.Fun.(*ast.Ident); ok && ident.Name == "append" {
+				for _, arg := range x.Args {
+					if s, ok := serialized(arg); ok {
+						return s, true
+					}
+				}
+			}
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok {
+				if s, ok := serialized(sel.X); ok {
+					return s, true
+				}
+			}
+
+			f := calledFunc(x, info)
+			if f == nil {
+				break
+			}
+			if matchCall(f, analyzer.config.UnorderedEncoders) {
+				for _, arg := range x.Args {
+					if tv, ok := info.Types[arg]; ok {
+						if reason, ok := mapReason(tv.Type); ok {
+							return serializedMap{x.Pos(), fmt.Sprintf("serialized by %s, which does not order map keys; %s", f.FullName(), reason)}, true
+						}
+					}
+				}
+			}
+			if helper, ok := decls[f]; ok {
+				if rangeMap, ok := iteratesMap(helper, info); ok {
+					return serializedMap{x.Pos(), fmt.Sprintf("built by %s, which iterates over %s in random order", f.Name(), rangeMap)}, true
+				}
+			}
+		}
+		return serializedMap{}, false
+	}
+
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range x.Lhs {
+				obj := object(lhs)
+				if obj == nil {
+					continue
+				}
+				var rhs ast.Expr
+				if len(x.Rhs) == len(x.Lhs) {
+					rhs = x.Rhs[i]
+				} else if i == 0 {
+					rhs = x.Rhs[0]
+				} else {
+					continue
+				}
+
+				if s, ok := serialized(rhs); ok {
+					tainted[obj] = s
+				} else if x.Tok != token.ADD_ASSIGN {
+					delete(tainted, obj)
+				}
+				if call, ok := rhs.(*ast.CallExpr); ok && len(call.Args) == 1 {
+					if f := calledFunc(call, info); f == nil || f.Name() != "NewEncoder" {
+						continue
+					}
+					if writer := object(call.Args[0]); writer != nil {
+						writers[obj] = writer
+					}
+				}
+			}
+		case *ast.CallExpr:
+			// enc.Encode(v) 는 반환 값이 아니라 encoder 가 쓰는 변수 (writer) 에 직렬화
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok {
+				if s, ok := serialized(x); ok {
+					if enc := object(sel.X); enc != nil {
+						if _, isTainted := tainted[enc]; !isTainted {
+							if writer, ok := writers[enc]; ok {
+								tainted[writer] = s
+							}
+						}
+					}
+				}
+			}
+
+			funcName := icg.NodeString(analyzer.fs, x.Fun)
+			if len(x.Args) == 0 || (!matchFunc(funcName, analyzer.config.PutState) && !matchFunc(funcName, analyzer.config.PublicSinks)) {
+				break
+			}
+			if s, ok := serialized(x.Args[len(x.Args)-1]); ok {
+				var ccw CCW = MAP_SERIALIZATION
+				position := analyzer.fs.Position(x.Pos())
+				analyzer.report(ccw, x, position, fmt.Sprintf("the value passed to %s is %s", funcName, s.reason))
+
+				finding := &analyzer.findings[len(analyzer.findings)-1]
+				origin := analyzer.fs.Position(s.pos)
+				finding.AddRelated(analyzer.analysisFile, origin.Line, "map is serialized")
+			}
+		}
+		return true
+	})
+}
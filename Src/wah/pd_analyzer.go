@@ -0,0 +1,101 @@
+package wah
+
+import (
+	"fmt"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// PDAnalyzer ...
+// private data (GetPrivateData, GetPrivateDataHash) 로 읽은 값이 channel 의 모든 peer 에 공개되는
+// PutState, SetEvent, shim.Success 로 전달되는 코드 탐지 (PRIVATE_DATA_LEAK)
+type PDAnalyzer struct {
+	analysisFile  string
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	sources       map[int]*icg.ControlOpcode // private data 를 저장하는 definition 라인, private data 를 읽는 call
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+func (analyzer *PDAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo) {
+	analyzer.analysisFile = analysisFile
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.sources = nil
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// findSources ... : 함수 안에서 private data 를 읽어 저장하는 definition 목록 (error 등 두 번째 이후의 반환 값은 제외)
+func (analyzer *PDAnalyzer) findSources() map[int]*icg.ControlOpcode {
+	sources := make(map[int]*icg.ControlOpcode)
+	for _, code := range analyzer.codeList {
+		call, ok := code.(*icg.ControlOpcode)
+		if !ok || call.Opcode() != icg.Call || !matchFunc(fmt.Sprint(call.Params().Front().Value), analyzer.config.PrivateDataReads) {
+			continue
+		}
+		if results := CallResults(call, analyzer.codeList); len(results) > 0 {
+			sources[results[0].GetLine()] = call
+		}
+	}
+	return sources
+}
+
+func (analyzer *PDAnalyzer) isPublicSink(funcName string) bool {
+	return matchFunc(funcName, analyzer.config.PutState) || matchFunc(funcName, analyzer.config.PublicSinks)
+}
+
+func (analyzer *PDAnalyzer) report(sink *icg.ControlOpcode, source *icg.ControlOpcode, path []icg.CodeInfo) {
+	var ccw CCW = PRIVATE_DATA_LEAK
+	sinkName := fmt.Sprint(sink.Params().Front().Value)
+	sourceName := fmt.Sprint(source.Params().Front().Value)
+
+	message := fmt.Sprintf("private data read by %s is passed to %s", sourceName, sinkName)
+	finding := newFinding(ccw, analyzer.analysisFile, sink.GetSourceLine(), 0, message)
+	finding.AddRelated(analyzer.analysisFile, source.GetSourceLine(), "private data is read by "+sourceName)
+	// path 는 sink 에서 private data 방향이므로 역순으로 기록 (private data 를 저장하는 definition 은 제외)
+	for i := len(path) - 2; i >= 0; i-- {
+		offset := fmt.Sprint(path[i].(*icg.StackOpcode).Params().Front().Next().Value)
+		finding.AddRelated(analyzer.analysisFile, path[i].GetSourceLine(), fmt.Sprintf("private data is assigned (offset %s)", offset))
+	}
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+func (analyzer *PDAnalyzer) PDAnalysis(block cfg.CFGBlock) int {
+	switch b := block.(type) {
+	case *cfg.CallBlock:
+		opcode := b.CodeList()[0]
+		callOp, ok := opcode.(*icg.ControlOpcode)
+		if !ok || callOp.Opcode() != icg.Call || !analyzer.isPublicSink(fmt.Sprint(callOp.Params().Front().Value)) {
+			break
+		}
+
+		if analyzer.sources == nil {
+			analyzer.sources = analyzer.findSources()
+		}
+		if len(analyzer.sources) == 0 {
+			break
+		}
+
+		// PutState(key, value), SetEvent(name, payload), shim.Success(payload) 모두 마지막 인자가 공개되는 값
+		args := CallArguments(callOp, analyzer.codeList)
+		if len(args) == 0 {
+			break
+		}
+		var source *icg.ControlOpcode
+		path, ok := TraceDefinition(args[len(args)-1], analyzer.chain, analyzer.codeList, func(def int) bool {
+			source = analyzer.sources[def]
+			return source != nil
+		})
+		if ok {
+			analyzer.report(callOp, source, path)
+		}
+	}
+	return analyzer.analysisCount
+}
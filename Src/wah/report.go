@@ -0,0 +1,157 @@
+package wah
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Report ...
+// 분석 결과 : 보고할 보안약점, //wah:ignore 주석으로 억제된 보안약점, 어떤 보안약점과도 일치하지 않는 주석,
+// baseline 에 기록되어 제외된 보안약점과 수정된 baseline 항목
+type Report struct {
+	Findings   []Finding
+	Suppressed []Finding
+	Stale      []Suppression
+	Baselined  []Finding
+	Fixed      []BaselineEntry
+	Errors     []AnalysisError
+
+	hasBaseline bool
+}
+
+// AnalysisError ...
+// 분석 중 발생한 오류 (해당 파일 또는 함수의 분석 결과는 불완전함)
+type AnalysisError struct {
+	File     string `json:"file"`
+	Function string `json:"function,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (e AnalysisError) Error() string {
+	if e.Function != "" {
+		return fmt.Sprintf("%s: %s: %s", e.File, e.Function, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// CountAtLeast ... : severity 이상인 보안약점의 개수
+func (r *Report) CountAtLeast(severity Severity) int {
+	count := 0
+	for _, f := range r.Findings {
+		if f.Severity >= severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Merge ... : 다른 패키지의 분석 결과를 합침
+func (r *Report) Merge(other Report) {
+	r.Findings = AppendFindings(r.Findings, other.Findings...)
+	r.Suppressed = AppendFindings(r.Suppressed, other.Suppressed...)
+	r.Stale = append(r.Stale, other.Stale...)
+	r.Baselined = AppendFindings(r.Baselined, other.Baselined...)
+	r.Fixed = append(r.Fixed, other.Fixed...)
+	r.Errors = append(r.Errors, other.Errors...)
+	r.hasBaseline = r.hasBaseline || other.hasBaseline
+}
+
+// Sort ... : 출력 결과를 항상 같은 순서로 유지
+func (r *Report) Sort() {
+	SortFindings(r.Findings)
+	SortFindings(r.Suppressed)
+	SortFindings(r.Baselined)
+	sort.SliceStable(r.Stale, func(i, j int) bool {
+		if r.Stale[i].File != r.Stale[j].File {
+			return r.Stale[i].File < r.Stale[j].File
+		}
+		return r.Stale[i].Line < r.Stale[j].Line
+	})
+	sort.SliceStable(r.Fixed, func(i, j int) bool {
+		if r.Fixed[i].File != r.Fixed[j].File {
+			return r.Fixed[i].File < r.Fixed[j].File
+		}
+		return r.Fixed[i].Line < r.Fixed[j].Line
+	})
+}
+
+// WriteText ... : 탐지된 보안약점을 콘솔 출력 형식으로 출력
+func WriteText(w io.Writer, report Report) {
+	findings := report.Findings
+	if len(findings) > 0 {
+		fmt.Fprintf(w, "chaincode weakness detected:\n")
+	}
+
+	for _, f := range findings {
+		fmt.Fprintf(w, "\t %s : %s (%s)\n", f.ID, f.Name, f.Severity)
+		if f.Message != "" {
+			fmt.Fprintf(w, "\t %s\n", f.Message)
+		}
+		fmt.Fprintf(w, "\t %s : %d\n", f.File, f.Line)
+		for _, related := range f.Related {
+			if related.Message != "" {
+				fmt.Fprintf(w, "\t %s : %d (%s)\n", related.File, related.Line, related.Message)
+			} else {
+				fmt.Fprintf(w, "\t %s : %d\n", related.File, related.Line)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.Stale) > 0 {
+		fmt.Fprintf(w, "stale suppression (does not match any weakness):\n")
+		for _, s := range report.Stale {
+			fmt.Fprintf(w, "\t %s : %d %s %s\n", s.File, s.Line, suppressDirective, strings.Join(s.IDs, ","))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.Fixed) > 0 {
+		fmt.Fprintf(w, "fixed baseline weakness:\n")
+		for _, entry := range report.Fixed {
+			fmt.Fprintf(w, "\t %s : %s (%s : %d)\n", entry.ID, entry.Name, entry.File, entry.Line)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\t Total weakness count : %d\n", len(findings))
+	if len(report.Suppressed) > 0 {
+		fmt.Fprintf(w, "\t Suppressed weakness count : %d\n", len(report.Suppressed))
+	}
+	if len(report.Baselined) > 0 {
+		fmt.Fprintf(w, "\t Baseline weakness count : %d\n", len(report.Baselined))
+	}
+}
+
+type jsonReport struct {
+	Total      int             `json:"total"`
+	Findings   []Finding       `json:"findings"`
+	Suppressed []Finding       `json:"suppressed,omitempty"`
+	Stale      []Suppression   `json:"staleSuppressions,omitempty"`
+	Baselined  int             `json:"baselined,omitempty"`
+	Fixed      []BaselineEntry `json:"fixed,omitempty"`
+	Errors     []AnalysisError `json:"errors,omitempty"`
+}
+
+// WriteJSON ... : 탐지된 보안약점을 JSON 형식으로 출력
+func WriteJSON(w io.Writer, report Report) error {
+	findings := report.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonReport{
+		Total:      len(findings),
+		Findings:   findings,
+		Suppressed: report.Suppressed,
+		Stale:      report.Stale,
+		Baselined:  len(report.Baselined),
+		Fixed:      report.Fixed,
+		Errors:     report.Errors,
+	})
+}
@@ -11,6 +11,7 @@ type RNGAnalyzer struct {
 	analysisFile  string
 	isSeedCall    bool
 	analysisCount int
+	findings      []Finding
 }
 
 func (analyzer *RNGAnalyzer) Init(analysisFile string) {
@@ -24,18 +25,16 @@ func (analyzer *RNGAnalyzer) Copy() *RNGAnalyzer {
 
 	newAnalyzer.analysisFile = analyzer.analysisFile
 	newAnalyzer.isSeedCall = analyzer.isSeedCall
+	newAnalyzer.analysisCount = analyzer.analysisCount
+	newAnalyzer.findings = analyzer.findings
 
 	return newAnalyzer
 }
 
-func (analyzer *RNGAnalyzer) printAlarm(linenum int) {
+func (analyzer *RNGAnalyzer) report(linenum int, funcName string) {
 	var ccw CCW = RANDOM_NUMBER_GENERATION
-	if isFirstDetect {
-		fmt.Printf("chaincode weakness detected:\n")
-		isFirstDetect = false
-	}
-	fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-	fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
+	message := fmt.Sprintf("\"%s\" is called without rand.Seed", funcName)
+	analyzer.findings = append(analyzer.findings, newFinding(ccw, analyzer.analysisFile, linenum, 0, message))
 }
 func (analyzer *RNGAnalyzer) RNGAnalysis(block cfg.CFGBlock) int {
 	switch b := block.(type) {
@@ -47,7 +46,7 @@ func (analyzer *RNGAnalyzer) RNGAnalysis(block cfg.CFGBlock) int {
 				if strings.Contains(funcName, "rand.Seed") {
 					analyzer.isSeedCall = true
 				} else if strings.Contains(funcName, "rand.") && !analyzer.isSeedCall {
-					analyzer.printAlarm(controlOp.GetSourceLine())
+					analyzer.report(controlOp.GetSourceLine(), funcName)
 					analyzer.analysisCount++
 				}
 			}
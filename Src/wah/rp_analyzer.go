@@ -0,0 +1,314 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// RPAnalyzer ...
+// 트랜잭션 진입 함수 (Invoke, contract 의 트랜잭션 함수) 에서 호출되는 함수의 panic, 단일 값 type assertion, nil 일 수 있는 map 에 대한 쓰기,
+// log.Fatal, os.Exit 호출 탐지 (RUNTIME_PANIC)
+// 호출 경로의 함수에 recover 를 호출하는 defer 가 있으면 recover 되는 것으로 보고 심각도를 낮춤 (log.Fatal, os.Exit 는 recover 되지 않음)
+type RPAnalyzer struct {
+	fs            *token.FileSet
+	info          *types.Info
+	files         []*ast.File
+	decls         map[*types.Func]*ast.FuncDecl
+	reachable     map[*types.Func]*callPath // 진입 함수에서 호출되는 함수, recover 되지 않는 경로를 우선
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+// callPath ... : 진입 함수에서 함수까지의 호출 경로 (recovered 는 경로의 함수 중 recover 하는 함수)
+type callPath struct {
+	caller    *types.Func
+	call      *ast.CallExpr
+	recovered *types.Func
+}
+
+func (analyzer *RPAnalyzer) Init(fs *token.FileSet, info *types.Info) {
+	analyzer.fs = fs
+	analyzer.info = info
+	analyzer.reachable = make(map[*types.Func]*callPath)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// Findings ...
+func (analyzer *RPAnalyzer) Findings() []Finding {
+	return analyzer.findings
+}
+
+// recovers ... : 함수에 recover 를 호출하는 defer 가 있는지 여부 (defer func() { recover() }(), defer 로 호출하는 패키지 안의 함수)
+func (analyzer *RPAnalyzer) recovers(decl *ast.FuncDecl) bool {
+	found := false
+	for _, stmt := range decl.Body.List {
+		deferStmt, ok := stmt.(*ast.DeferStmt)
+		if !ok {
+			continue
+		}
+		var body ast.Node = deferStmt.Call.Fun
+		if funcLit, ok := deferStmt.Call.Fun.(*ast.FuncLit); ok {
+			body = funcLit.Body
+		} else if helper, ok := analyzer.decls[calledFunc(deferStmt.Call, analyzer.info)]; ok {
+			body = helper.Body
+		}
+		ast.Inspect(body, func(node ast.Node) bool {
+			if call, ok := node.(*ast.CallExpr); ok && analyzer.isBuiltin(call, "recover") {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+func (analyzer *RPAnalyzer) isBuiltin(call *ast.CallExpr, name string) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	builtin, ok := analyzer.info.Uses[ident].(*types.Builtin)
+	return ok && builtin.Name() == name
+}
+
+// visit ... : 진입 함수에서 호출되는 함수를 따라가며 호출 경로를 기록
+// 이미 recover 되지 않는 경로로 방문한 함수는 다시 방문하지 않음
+func (analyzer *RPAnalyzer) visit(f *types.Func, path *callPath) {
+	if old, ok := analyzer.reachable[f]; ok && (old.recovered == nil || path.recovered != nil) {
+		return
+	}
+	analyzer.reachable[f] = path
+
+	decl := analyzer.decls[f]
+	recovered := path.recovered
+	if recovered == nil && analyzer.recovers(decl) {
+		recovered = f
+	}
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if callee := calledFunc(call, analyzer.info); callee != nil && analyzer.decls[callee] != nil {
+				analyzer.visit(callee, &callPath{caller: f, call: call, recovered: recovered})
+			}
+		}
+		return true
+	})
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
+// commaOk ... : 두 값을 받는 type assertion (v, ok := x.(T)) 목록
+func commaOk(body *ast.BlockStmt) map[*ast.TypeAssertExpr]bool {
+	res := make(map[*ast.TypeAssertExpr]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		var lhs int
+		var rhs []ast.Expr
+		switch x := node.(type) {
+		case *ast.AssignStmt:
+			lhs, rhs = len(x.Lhs), x.Rhs
+		case *ast.ValueSpec:
+			lhs, rhs = len(x.Names), x.Values
+		default:
+			return true
+		}
+		if lhs == 2 && len(rhs) == 1 {
+			if assert, ok := unparen(rhs[0]).(*ast.TypeAssertExpr); ok {
+				res[assert] = true
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// nilMap ... : map 에 대한 쓰기 (m[k] = v) 의 map 변수가 이전에 값이 할당되지 않은 채 var m map[K]V 로 선언된 변수인지 여부
+// 패키지 수준 변수는 초기값이 없고 패키지 안에서 할당되지 않는 경우
+func (analyzer *RPAnalyzer) nilMap(decl *ast.FuncDecl, index *ast.IndexExpr) (*types.Var, bool) {
+	ident, ok := unparen(index.X).(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	v, ok := analyzer.info.Uses[ident].(*types.Var)
+	if !ok {
+		return nil, false
+	}
+	if _, isMap := v.Type().Underlying().(*types.Map); !isMap {
+		return nil, false
+	}
+
+	// map 이 선언된 범위 (함수 안의 변수는 이 함수, 패키지 수준 변수는 모든 파일)
+	var scope []ast.Node
+	if decl.Pos() <= v.Pos() && v.Pos() < decl.End() {
+		scope = []ast.Node{decl.Body}
+	} else if v.Parent() == v.Pkg().Scope() {
+		for _, f := range analyzer.files {
+			scope = append(scope, f)
+		}
+	} else {
+		return nil, false // 매개변수, 반환 값
+	}
+
+	initialized := false
+	for _, node := range scope {
+		ast.Inspect(node, func(node ast.Node) bool {
+			switch x := node.(type) {
+			case *ast.ValueSpec:
+				for _, name := range x.Names {
+					if analyzer.info.Defs[name] == v && len(x.Values) > 0 {
+						initialized = true
+					}
+				}
+			case *ast.AssignStmt:
+				for _, lhs := range x.Lhs {
+					ident, ok := unparen(lhs).(*ast.Ident)
+					if !ok || (analyzer.info.Defs[ident] != v && analyzer.info.Uses[ident] != v) {
+						continue
+					}
+					// 함수 안의 변수는 쓰기 이전에 할당된 경우만 초기화된 것으로 봄
+					if len(scope) > 1 || x.Pos() < index.Pos() {
+						initialized = true
+					}
+				}
+			case *ast.UnaryExpr:
+				// &m 으로 다른 함수에 전달되어 할당되는 경우
+				if ident, ok := unparen(x.X).(*ast.Ident); ok && x.Op == token.AND && analyzer.info.Uses[ident] == v {
+					initialized = true
+				}
+			}
+			return !initialized
+		})
+	}
+	return v, !initialized
+}
+
+// failures ... : 함수 안에서 panic 이 발생할 수 있는 위치와 설명
+func (analyzer *RPAnalyzer) failures(decl *ast.FuncDecl) []panicSite {
+	var res []panicSite
+	checked := commaOk(decl.Body)
+
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.CallExpr:
+			f := calledFunc(x, analyzer.info)
+			switch {
+			case analyzer.isBuiltin(x, "panic"):
+				res = append(res, panicSite{node: x, message: "panic is called"})
+			case matchCall(f, analyzer.config.ExitFuncs):
+				res = append(res, panicSite{node: x, message: fmt.Sprintf("%s stops the chaincode process", icg.NodeString(analyzer.fs, x.Fun)), exit: true})
+			case matchCall(f, analyzer.config.PanicFuncs):
+				res = append(res, panicSite{node: x, message: fmt.Sprintf("%s panics", icg.NodeString(analyzer.fs, x.Fun))})
+			}
+		case *ast.TypeAssertExpr:
+			if x.Type != nil && !checked[x] {
+				message := fmt.Sprintf("type assertion %s panics if the value is not %s", icg.NodeString(analyzer.fs, x), icg.NodeString(analyzer.fs, x.Type))
+				res = append(res, panicSite{node: x, message: message})
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range x.Lhs {
+				res = analyzer.mapWrite(decl, x, lhs, res)
+			}
+		case *ast.IncDecStmt:
+			res = analyzer.mapWrite(decl, x, x.X, res)
+		}
+		return true
+	})
+	return res
+}
+
+// mapWrite ... : stmt 가 nil 일 수 있는 map 의 원소 (lhs) 에 쓰는 경우 panic 위치를 추가
+func (analyzer *RPAnalyzer) mapWrite(decl *ast.FuncDecl, stmt ast.Stmt, lhs ast.Expr, res []panicSite) []panicSite {
+	index, ok := unparen(lhs).(*ast.IndexExpr)
+	if !ok {
+		return res
+	}
+	if v, ok := analyzer.nilMap(decl, index); ok {
+		message := fmt.Sprintf("assignment to %s panics because map %s may be nil", icg.NodeString(analyzer.fs, index), v.Name())
+		res = append(res, panicSite{node: stmt, message: message})
+	}
+	return res
+}
+
+// panicSite ... : panic 이 발생할 수 있는 위치 (exit 는 process 를 종료하여 recover 되지 않는 호출)
+type panicSite struct {
+	node    ast.Node
+	message string
+	exit    bool
+}
+
+func (analyzer *RPAnalyzer) report(f *types.Func, site panicSite) {
+	path := analyzer.reachable[f]
+
+	var ccw CCW = RUNTIME_PANIC
+	position := analyzer.fs.Position(site.node.Pos())
+	message := site.message
+	recovered := path.recovered
+	if recovered == nil && analyzer.recovers(analyzer.decls[f]) {
+		recovered = f
+	}
+	severity := Medium
+	switch {
+	case site.exit:
+		severity = High
+	case recovered != nil:
+		message += fmt.Sprintf(" (recovered by the deferred function in %s)", recovered.Name())
+		severity = Low
+	default:
+		message += " and is not recovered"
+	}
+	finding := newFinding(ccw, position.Filename, position.Line, position.Column, message)
+	finding.Function = f.Name()
+	finding.Severity = severity
+
+	// 진입 함수까지의 호출 경로
+	for p := path; p.caller != nil; p = analyzer.reachable[p.caller] {
+		callPos := analyzer.fs.Position(p.call.Pos())
+		finding.AddRelated(callPos.Filename, callPos.Line, "called from "+p.caller.Name())
+	}
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// Analysis ... : 패키지의 모든 파일에서 진입 함수 (Invoke, contract 의 트랜잭션 함수) 와 진입 함수가 호출하는 함수를 분석
+func (analyzer *RPAnalyzer) Analysis(files []*ast.File) int {
+	analyzer.files = files
+	decls, entries := transactionFuncs(files, analyzer.info)
+	analyzer.decls = decls
+
+	for _, entry := range entries {
+		if f, ok := analyzer.info.Defs[entry.Name].(*types.Func); ok {
+			analyzer.visit(f, &callPath{})
+		}
+	}
+
+	// 선언 순서대로 보고
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			f, ok := analyzer.info.Defs[funcDecl.Name].(*types.Func)
+			if !ok || analyzer.reachable[f] == nil {
+				continue
+			}
+			for _, site := range analyzer.failures(funcDecl) {
+				analyzer.report(f, site)
+			}
+		}
+	}
+	return analyzer.analysisCount
+}
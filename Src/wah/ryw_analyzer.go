@@ -7,8 +7,6 @@ import (
 	"WAH_prototype_go-master/Src/icg/symbolTable"
 	"fmt"
 	"github.com/mitchellh/go-z3"
-	"io/ioutil"
-	"log"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -21,17 +19,29 @@ type RYWAnalyzer struct {
 	chain         vfg.DUChain
 	codeList      []icg.CodeInfo
 	analysisCount int
+	findings      []Finding
 	ctx           *z3.Context
 	solver        *z3.Solver
 	symbolList    map[string]*SMTSymbol
 	literalSymbol map[string]*z3.AST
 	opStack       []string
 
-	keyFormula  string
-	putStateKey string
+	writes    []rywWrite // 같은 transaction 에서 PutState, DelState 한 key
+	solverErr error      // z3 를 실행할 수 없는 경우 (이후의 검사는 생략)
 
 	litTable *symbolTable.LiteralTable
+	config   *Config
 }
+
+// rywWrite ... : ledger 에 쓰거나 삭제하는 호출 (formula 는 key 를 계산하는 SMT formula, key 는 key 의 SMT 식)
+type rywWrite struct {
+	formula  string
+	key      string
+	line     int
+	funcName string
+	isDelete bool
+}
+
 type SMTSymbol struct {
 	symbolName string
 	symbolType icg.SilType
@@ -52,9 +62,21 @@ func (analyzer *RYWAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeLi
 
 	analyzer.symbolList = make(map[string]*SMTSymbol)
 	analyzer.literalSymbol = make(map[string]*z3.AST)
-	analyzer.keyFormula = ""
+	analyzer.writes = nil
 	analyzer.litTable = litTable
+	analyzer.config = DefaultConfig()
+
+}
 
+// Copy ... : 분기 이전의 쓰기 목록을 유지하기 위한 복사본 (symbol 은 함수 전체에서 공유)
+func (analyzer *RYWAnalyzer) Copy() *RYWAnalyzer {
+	newAnalyzer := new(RYWAnalyzer)
+	*newAnalyzer = *analyzer
+	newAnalyzer.defList = append([]int(nil), analyzer.defList...)
+	newAnalyzer.putStateLine = append([]int(nil), analyzer.putStateLine...)
+	newAnalyzer.writes = append([]rywWrite(nil), analyzer.writes...)
+
+	return newAnalyzer
 }
 
 func (analyzer *RYWAnalyzer) FindKeyVar(call icg.CodeInfo) icg.CodeInfo {
@@ -94,7 +116,7 @@ func (analyzer *RYWAnalyzer) FindKeyVar(call icg.CodeInfo) icg.CodeInfo {
 			if isDebug {
 				fmt.Printf("Inverse analysis completed!\n")
 				fmt.Printf("\t Key parameter : %s\n\n", fmt.Sprint(res[len(res)-1]))
-				fmt.Println("--------------------------------------------------------------------------------\n")
+				fmt.Print("--------------------------------------------------------------------------------\n\n")
 			}
 			break
 		}
@@ -103,15 +125,17 @@ func (analyzer *RYWAnalyzer) FindKeyVar(call icg.CodeInfo) icg.CodeInfo {
 	return res[len(res)-1]
 
 }
-func (analyzer *RYWAnalyzer) printAlarm(line int) {
-	var ccw CCW = READ_YOUR_WRITE
-	if isFirstDetect {
-		fmt.Printf("chaincode weakness detected:\n")
-		isFirstDetect = false
-	}
-	fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-	fmt.Printf("\t %s : %d\n", analyzer.analysisFile, analyzer.putStateLine[len(analyzer.putStateLine)-1])
-	fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, line)
+// report ... : formula 는 PutState 와 GetState 의 key 가 같은지 검사한 SMT formula (z3 를 사용하지 않은 경우 "")
+func (analyzer *RYWAnalyzer) report(line int, putStateLine int, formula string) {
+	analyzer.reportRead(READ_YOUR_WRITE, line, "GetState reads a key written by PutState in the same transaction", putStateLine, "PutState", formula)
+}
+
+// reportRead ... : 같은 transaction 에서 쓰거나 삭제한 key 를 읽는 보안약점 (READ_YOUR_WRITE, READ_AFTER_DELETE)
+func (analyzer *RYWAnalyzer) reportRead(ccw CCW, line int, message string, writeLine int, writeName string, formula string) {
+	finding := newFinding(ccw, analyzer.analysisFile, line, 0, message)
+	finding.AddRelated(analyzer.analysisFile, writeLine, writeName)
+	finding.Evidence = formula
+	analyzer.findings = append(analyzer.findings, finding)
 	analyzer.analysisCount++
 }
 func (analyzer *RYWAnalyzer) RYWAnalysis(block cfg.CFGBlock) int {
@@ -121,14 +145,14 @@ func (analyzer *RYWAnalyzer) RYWAnalysis(block cfg.CFGBlock) int {
 		if callOp, ok := opcode.(*icg.ControlOpcode); ok {
 			funcName := fmt.Sprint(callOp.Params().Front().Value)
 			// 함수가 putstate 함수인 경우
-			if strings.Contains(funcName, ".PutState") {
+			if matchFunc(funcName, analyzer.config.PutState) {
 				keyOpcode := analyzer.FindKeyVar(callOp)
 				keyParam := keyOpcode.(*icg.StackOpcode)
 				offset := fmt.Sprint(keyParam.Params().Front().Next().Value)
 				definition, _ := analyzer.chain.LookUpDefOfUse(offset, keyParam.GetLine())
 				analyzer.defList = append(analyzer.defList, definition)
 				analyzer.putStateLine = append(analyzer.putStateLine, callOp.GetSourceLine())
-			} else if strings.Contains(funcName, ".GetState") {
+			} else if matchFunc(funcName, analyzer.config.GetState) {
 				// putstate가 호출되엇는지
 				if len(analyzer.defList) > 0 {
 					keyOpcode := analyzer.FindKeyVar(callOp)
@@ -138,15 +162,7 @@ func (analyzer *RYWAnalyzer) RYWAnalysis(block cfg.CFGBlock) int {
 
 					for i, def := range analyzer.defList {
 						if def == definition {
-							var ccw CCW = READ_YOUR_WRITE
-							if isFirstDetect {
-								fmt.Printf("chaincode weakness detected:\n")
-								isFirstDetect = false
-							}
-							fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-							fmt.Printf("\t %s : %d\n", analyzer.analysisFile, keyParam.GetSourceLine())
-							fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, analyzer.putStateLine[i])
-							analyzer.analysisCount++
+							analyzer.report(keyParam.GetSourceLine(), analyzer.putStateLine[i], "")
 						}
 					}
 				}
@@ -214,7 +230,7 @@ func (analyzer *RYWAnalyzer) makeBinaryExpression(op icg.ArithmeticOpcode, sym1
 	case icg.Div:
 		formula = fmt.Sprintf("(/ %s %s)", sym1, sym2)
 	case icg.Mod:
-		formula = fmt.Sprintf("(% %s %s)", sym1, sym2)
+		formula = fmt.Sprintf("(mod %s %s)", sym1, sym2)
 	case icg.Eq:
 		formula = fmt.Sprintf("(= %s %s)", sym1, sym2)
 	case icg.Ne:
@@ -247,11 +263,21 @@ func (analyzer *RYWAnalyzer) makeBinaryExpression(op icg.ArithmeticOpcode, sym1
 
 	return formula
 }
-func execProgram(program string, args ...string) []byte{
-	cmd := exec.Command(program, args...)
+// checkSat ... : SMT-LIB formula 를 z3 의 표준 입력으로 전달하여 만족 가능 (sat) 여부를 검사
+// z3 는 PATH 에서 찾으며 파일을 만들지 않으므로 여러 분석을 동시에 실행해도 서로 영향을 주지 않음
+func checkSat(formula string) (bool, error) {
+	program, err := exec.LookPath("z3")
+	if err != nil {
+		return false, err
+	}
+	cmd := exec.Command(program, "-in", "-smt2")
+	cmd.Stdin = strings.NewReader(formula)
 
-	res, _ := cmd.Output()
-	return res
+	res, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(res)) == "sat", nil
 }
 func (analyzer *RYWAnalyzer) makeSymbol(opcode icg.StackOpcode) *SMTSymbol {
 
@@ -278,6 +304,10 @@ func (analyzer *RYWAnalyzer) makeSMTFormula(genRange []icg.CodeInfo) string {
 					lodFormula := analyzer.makeSMTFormula(genRange)
 					formulaList = append(formulaList, lodFormula)
 				}
+				// 함수 안에 정의가 없는 변수 (매개변수, 전역 변수) 는 자유 변수
+				if _, ok := analyzer.symbolList[offset]; !ok {
+					analyzer.symbolList[offset] = analyzer.makeSymbol(*c)
+				}
 
 				analyzer.opStack = append(analyzer.opStack, analyzer.symbolList[offset].symbolName)
 
@@ -305,12 +335,12 @@ func (analyzer *RYWAnalyzer) makeSMTFormula(genRange []icg.CodeInfo) string {
 					literalOffset = literalOffset[1:]
 					literalAdd,err := strconv.Atoi(literalOffset)
 					if err != nil {
-						log.Fatal("error")
+						panic(fmt.Sprintf("invalid literal offset %s", literalOffset))
 					}
 
 					lit,ok := analyzer.litTable.GetLiteral(literalAdd)
 					if !ok {
-						log.Fatal("error : not found literal")
+						panic(fmt.Sprintf("literal %d not found", literalAdd))
 					}
 
 					analyzer.opStack = append(analyzer.opStack, lit)
@@ -357,6 +387,9 @@ func (analyzer *RYWAnalyzer) GetFormulaGenRange(variable icg.CodeInfo, offset st
 	}
 
 	defCodeIndex := findSILIndex(reverseCodeList, defLine)
+	if defCodeIndex == -1 { // 함수 안에 정의가 없는 경우 (매개변수, 전역 변수)
+		return nil
+	}
 
 	analysisRange := reverseCodeList[defCodeIndex:]
 	sp := -analysisRange[0].GetPopParameterNum() //pop이 소모자, push가 판매
@@ -422,69 +455,168 @@ func SMTSymTypetoString(silType icg.SilType) string {
 
 	return res
 }
-func (analyzer *RYWAnalyzer) RYWAnalysisUsedZ3(block cfg.CFGBlock) {
-	switch b := block.(type) {
-	case *cfg.CallBlock:
-		opcode := b.CodeList()[0]
-		if callOp, ok := opcode.(*icg.ControlOpcode); ok {
-			funcName := fmt.Sprint(callOp.Params().Front().Value)
+// keyTerm ... : key 인자를 계산하는 SMT formula 와 key 의 SMT 식 (문자열 literal 은 literal 자체)
+func (analyzer *RYWAnalyzer) keyTerm(keyParam *icg.StackOpcode) (string, string, bool) {
+	if keyParam.Opcode() == icg.Lda && keyParam.Type() == icg.Sp {
+		analyzer.makeSMTFormula([]icg.CodeInfo{keyParam})
+		lit := analyzer.opStack[len(analyzer.opStack)-1]
+		analyzer.opStack = analyzer.opStack[0 : len(analyzer.opStack)-1]
+		return "", lit, true
+	}
 
-			// 함수가 putstate 함수인 경우
-			if strings.Contains(funcName, ".PutState") {
-				keyOpcode := analyzer.FindKeyVar(callOp)
-				keyParam := keyOpcode.(*icg.StackOpcode)
-				offset := fmt.Sprint(keyParam.Params().Front().Next().Value)
+	offset := fmt.Sprint(keyParam.Params().Front().Next().Value)
+	formulaRange := analyzer.GetFormulaGenRange(keyParam, offset)
+	formula := analyzer.makeSMTFormula(formulaRange)
 
-				formulaRange := analyzer.GetFormulaGenRange(keyParam, offset)
+	if isDebug {
+		fmt.Println("formulaGenRange")
+		for _,sil := range formulaRange {
+			fmt.Println(sil.String())
+		}
+	}
 
-				formula := analyzer.makeSMTFormula(formulaRange)
+	if _, ok := analyzer.symbolList[offset]; !ok {
+		sym := analyzer.makeSymbol(*keyParam)
+		if sym == nil {
+			return "", "", false
+		}
+		analyzer.symbolList[offset] = sym
+	}
+	return formula, analyzer.symbolList[offset].symbolName, true
+}
 
-				analyzer.keyFormula = formula
-				analyzer.putStateKey = offset
-				analyzer.putStateLine = append(analyzer.putStateLine, callOp.GetSourceLine())
-				//analyzer.solver.Assert(formula)
+// rangeKeys ... : GetStateByRange 의 startKey, endKey 인자
+func (analyzer *RYWAnalyzer) rangeKeys(callOp *icg.ControlOpcode) []*icg.StackOpcode {
+	var res []*icg.StackOpcode
+	args := CallArguments(callOp, analyzer.codeList)
+	if len(args) < 2 {
+		return nil
+	}
+	for _, arg := range args[0:2] {
+		var keyParam *icg.StackOpcode
+		for _, code := range arg {
+			if code.Opcode() == icg.Lod || code.Opcode() == icg.Lda {
+				keyParam = code.(*icg.StackOpcode)
+				break
+			}
+		}
+		if keyParam == nil {
+			return nil
+		}
+		res = append(res, keyParam)
+	}
+	return res
+}
 
-			} else if strings.Contains(funcName, ".GetState") {
-				if analyzer.keyFormula != "" {
-					keyOpcode := analyzer.FindKeyVar(callOp)
-					keyParam := keyOpcode.(*icg.StackOpcode)
-					offset := fmt.Sprint(keyParam.Params().Front().Next().Value)
-					formulaRange := analyzer.GetFormulaGenRange(keyParam, offset)
+// checkRead ... : 읽는 key (range query 는 startKey <= key < endKey, endKey 가 "" 이면 상한 없음) 가
+// 같은 transaction 에서 쓰거나 삭제한 key 와 같을 수 있는지 z3 로 검사
+// 가장 최근의 PutState, DelState 를 각각 한 번만 보고
+func (analyzer *RYWAnalyzer) checkRead(callOp *icg.ControlOpcode, keyParams []*icg.StackOpcode, isRange bool) {
+	var formulaList, keys []string
+	for _, keyParam := range keyParams {
+		formula, key, ok := analyzer.keyTerm(keyParam)
+		if !ok {
+			return
+		}
+		if formula != "" {
+			formulaList = append(formulaList, formula)
+		}
+		keys = append(keys, key)
+	}
 
-					formula := analyzer.makeSMTFormula(formulaRange)
-					//analyzer.solver.Assert(formula)
-					weaknessFormula := fmt.Sprintf("(and %s %s)", analyzer.keyFormula, formula)
+	readName := fmt.Sprint(callOp.Params().Front().Value)
+	readName = readName[strings.LastIndex(readName, ".")+1:]
+	reported := make(map[CCW]bool)
+	for i := len(analyzer.writes) - 1; i >= 0; i-- {
+		write := analyzer.writes[i]
+		var ccw CCW = READ_YOUR_WRITE
+		if write.isDelete {
+			ccw = READ_AFTER_DELETE
+		}
+		if reported[ccw] {
+			continue
+		}
 
-					fmt.Println("formulaGenRange")
-					for _,sil := range formulaRange {
-						fmt.Println(sil.String())
-					}
+		keyEq := fmt.Sprintf("(= %s %s)", write.key, keys[0])
+		if isRange {
+			keyEq = fmt.Sprintf("(and (str.<= %s %s) (or (= %s \"\") (str.< %s %s)))", keys[0], write.key, keys[1], write.key, keys[1])
+		}
+		weaknessFormula := keyEq
+		for j := len(formulaList) - 1; j >= 0; j-- {
+			weaknessFormula = fmt.Sprintf("(and %s %s)", formulaList[j], weaknessFormula)
+		}
+		if write.formula != "" {
+			weaknessFormula = fmt.Sprintf("(and %s %s)", write.formula, weaknessFormula)
+		}
 
-					keyEq := fmt.Sprintf("(= %s %s)", analyzer.symbolList[analyzer.putStateKey].symbolName, analyzer.symbolList[offset].symbolName)
+		weaknessFormula = fmt.Sprintf("(assert %s)\n", weaknessFormula)
+		for _,sym := range analyzer.symbolList {
+			weaknessFormula = fmt.Sprintf("(declare-const %s %s)\n",sym.symbolName,SMTSymTypetoString( sym.symbolType)) +weaknessFormula
+		}
+		weaknessFormula += "(check-sat)\n"
 
-					//analyzer.solver.Assert(keyEq)
-					weaknessFormula = fmt.Sprintf("(and %s %s)", weaknessFormula, keyEq)
+		if analyzer.solverErr != nil {
+			return
+		}
+		sat, err := checkSat(weaknessFormula)
+		if err != nil {
+			// SMT solver 를 실행할 수 없으면 READ_YOUR_WRITE, READ_AFTER_DELETE 를 판단할 수 없으므로 이후의 검사는 생략하고 분석 오류로 기록
+			analyzer.solverErr = err
+			return
+		}
+		if !sat {
+			if isDebug {
+				fmt.Println("RYW not exist")
+			}
+			continue
+		}
+		reported[ccw] = true
+
+		var message string
+		switch {
+		case isRange && write.isDelete:
+			message = fmt.Sprintf("%s may return a key deleted by %s in the same transaction, because the deletion is not applied until commit", readName, write.funcName)
+		case isRange:
+			message = fmt.Sprintf("%s may not return a key written by %s in the same transaction", readName, write.funcName)
+		case write.isDelete:
+			message = fmt.Sprintf("%s reads a key deleted by %s in the same transaction and still returns the deleted value", readName, write.funcName)
+		default:
+			message = "GetState reads a key written by PutState in the same transaction"
+		}
+		analyzer.reportRead(ccw, keyParams[0].GetSourceLine(), message, write.line, write.funcName, weaknessFormula)
+	}
+}
 
-					weaknessFormula = fmt.Sprintf("(assert %s)\n", weaknessFormula)
-					for _,sym := range analyzer.symbolList {
-						weaknessFormula = fmt.Sprintf("(declare-const %s %s)\n",sym.symbolName,SMTSymTypetoString( sym.symbolType)) +weaknessFormula
-					}
-					weaknessFormula += "(check-sat)\n"
+func (analyzer *RYWAnalyzer) RYWAnalysisUsedZ3(block cfg.CFGBlock) {
+	switch b := block.(type) {
+	case *cfg.CallBlock:
+		opcode := b.CodeList()[0]
+		if callOp, ok := opcode.(*icg.ControlOpcode); ok {
+			funcName := fmt.Sprint(callOp.Params().Front().Value)
 
+			// 함수가 putstate, delstate 함수인 경우
+			if isDelete := matchFunc(funcName, analyzer.config.DelState); isDelete || matchFunc(funcName, analyzer.config.PutState) {
+				keyOpcode := analyzer.FindKeyVar(callOp)
+				keyParam := keyOpcode.(*icg.StackOpcode)
 
-					data := []byte(weaknessFormula)
-					ioutil.WriteFile("./ryw.smt",data,0644)
+				formula, key, ok := analyzer.keyTerm(keyParam)
+				if !ok {
+					return
+				}
+				writeName := funcName[strings.LastIndex(funcName, ".")+1:]
+				analyzer.writes = append(analyzer.writes, rywWrite{formula: formula, key: key, line: callOp.GetSourceLine(), funcName: writeName, isDelete: isDelete})
+				analyzer.putStateLine = append(analyzer.putStateLine, callOp.GetSourceLine())
+				//analyzer.solver.Assert(formula)
 
-					res := execProgram("./z3" , "-smt2","./ryw.smt")
-					analysisRes := string(res)
-					analysisRes = analysisRes[:len(analysisRes)-1]
-					//fmt.Println(string(res))
-					if analysisRes ==  "sat"{
-						analyzer.printAlarm(keyParam.GetSourceLine())
-					}else {
-						fmt.Println("RYW not exist")
+			} else if len(analyzer.writes) > 0 {
+				// .GetState 는 GetStateByRange 에도 포함되므로 range query 를 먼저 비교
+				if matchFunc(funcName, analyzer.config.RangeReads) {
+					if keyParams := analyzer.rangeKeys(callOp); keyParams != nil {
+						analyzer.checkRead(callOp, keyParams, true)
 					}
-
+				} else if matchFunc(funcName, analyzer.config.GetState) {
+					keyOpcode := analyzer.FindKeyVar(callOp)
+					analyzer.checkRead(callOp, []*icg.StackOpcode{keyOpcode.(*icg.StackOpcode)}, false)
 				}
 			}
 		}
@@ -0,0 +1,346 @@
+package wah
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// sampleCase ...
+// testSrc 의 sample 하나에서 보고되어야 하는 보안약점의 라인 (lines 가 비어 있으면 보고되지 않아야 함)
+type sampleCase struct {
+	file   string
+	id     string
+	lines  []int
+	solver bool // z3 로 검사하는 보안약점 (z3 가 없으면 생략)
+}
+
+var sampleCases = []sampleCase{
+	{"pagination_query.go", "CCW-007", []int{17}, false},
+	{"pagination_query.go", "CCW-009", []int{17, 23}, false},
+	{"systime.go", "CCW-011", []int{22}, false},
+	{"systime_safe.go", "CCW-011", nil, false},
+	{"cross_chaincode.go", "CCW-012", []int{19}, false},
+	{"cross_chaincode_safe.go", "CCW-012", nil, false},
+	{"private_data.go", "CCW-013", []int{23}, false},
+	{"private_data_safe.go", "CCW-013", nil, false},
+	{"private_data_hash.go", "CCW-013", []int{22}, false},
+	{"duplicate_write.go", "CCW-014", []int{20, 28}, false},
+	{"duplicate_write_safe.go", "CCW-014", nil, false},
+	{"iterator.go", "CCW-015", []int{16}, false},
+	{"iterator_safe.go", "CCW-015", nil, false},
+	{"iterator_pagination.go", "CCW-015", []int{16}, false},
+	{"pagination_query.go", "CCW-015", nil, false},
+	{"argument_length.go", "CCW-016", []int{19, 20}, false},
+	{"argument_length_safe.go", "CCW-016", nil, false},
+	{"map_serialization.go", "CCW-017", []int{32}, false},
+	{"map_serialization_safe.go", "CCW-017", nil, false},
+	{"access_control.go", "CCW-018", []int{24}, false},
+	{"access_control_safe.go", "CCW-018", nil, false},
+	{"external_access.go", "CCW-019", []int{21}, false},
+	{"external_access.go", "CCW-020", []int{25}, false},
+	{"external_access.go", "CCW-021", []int{29}, false},
+	{"external_access_safe.go", "CCW-010", nil, false},
+	{"external_access_safe.go", "CCW-011", nil, false},
+	{"external_access_safe.go", "CCW-019", nil, false},
+	{"external_access_safe.go", "CCW-020", nil, false},
+	{"external_access_safe.go", "CCW-021", nil, false},
+	{"runtime_panic.go", "CCW-022", []int{21, 26, 36}, false},
+	{"runtime_panic_safe.go", "CCW-022", nil, false},
+	{"unchecked_arithmetic.go", "CCW-023", []int{30}, true},
+	{"unchecked_arithmetic_safe.go", "CCW-023", nil, true},
+	{"read_after_delete.go", "CCW-024", []int{22}, true},
+	{"read_after_delete_safe.go", "CCW-024", nil, false},
+	{"key_input.go", "CCW-025", []int{23}, false},
+	{"key_input_safe.go", "CCW-025", nil, false},
+	{"key_input_branch.go", "CCW-025", []int{36, 38}, false},
+	{"global_state.go", "CCW-026", []int{33, 35, 40, 40}, false},
+	{"global_state_safe.go", "CCW-026", nil, false},
+}
+
+// sampleAnalyzer ... : sample 파일을 하나씩 타입 검사하여 분석 (모든 sample 이 importer 를 공유하므로 shim 등은 한 번만 타입 검사)
+type sampleAnalyzer struct {
+	dir     string // sample 이 있는 디렉터리
+	fs      *token.FileSet
+	imp     types.Importer
+	reports map[string]Report
+}
+
+func (sa *sampleAnalyzer) Init() {
+	sa.dir = filepath.Join("..", "testSrc")
+	sa.fs = token.NewFileSet()
+	sa.imp = importer.ForCompiler(sa.fs, "source", nil)
+	sa.reports = make(map[string]Report)
+}
+
+func (sa *sampleAnalyzer) analyze(t *testing.T, file string) Report {
+	report, err := sa.load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range report.Errors {
+		t.Logf("analysis error: %v", e)
+	}
+	return report
+}
+
+func (sa *sampleAnalyzer) load(file string) (Report, error) {
+	if report, ok := sa.reports[file]; ok {
+		return report, nil
+	}
+
+	f, info, err := sa.check(file)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := AnalysisFiles(sa.fs, []*ast.File{f}, info, DefaultConfig())
+	sa.reports[file] = report
+	return report, nil
+}
+
+// check ... : sa.dir 의 sample 을 parse 하여 main package 로 타입 검사
+func (sa *sampleAnalyzer) check(file string) (*ast.File, *types.Info, error) {
+	f, err := parser.ParseFile(sa.fs, filepath.Join(sa.dir, file), nil, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	conf := types.Config{Importer: sa.imp}
+	if _, err := conf.Check("main", sa.fs, []*ast.File{f}, info); err != nil {
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+func TestSamples(t *testing.T) {
+	sa := new(sampleAnalyzer)
+	sa.Init()
+
+	_, z3Err := exec.LookPath("z3")
+	for _, c := range sampleCases {
+		runSample(t, sa, c, z3Err)
+	}
+}
+
+// contractSampleCases ... : contractapi 로 작성된 sample (ctx.GetStub() 으로 호출한 stub 메소드를 CFG, DU chain 기반 탐지가 인식하는지 확인)
+// contractapi 는 go.mod 에 없으므로 sample 은 go build ./... 에 포함되지 않는 testdata 에 두고, package 를 찾을 수 없으면 생략
+var contractSampleCases = []sampleCase{
+	{"contract_api.go", "CCW-014", []int{16}, false},
+	{"contract_api.go", "CCW-008", []int{24}, true},
+}
+
+func TestContractAPISamples(t *testing.T) {
+	sa := new(sampleAnalyzer)
+	sa.Init()
+	sa.dir = "testdata"
+	if _, err := sa.imp.Import(contractPackage); err != nil {
+		t.Skipf("%s can not be loaded: %v", contractPackage, err)
+	}
+
+	_, z3Err := exec.LookPath("z3")
+	for _, c := range contractSampleCases {
+		runSample(t, sa, c, z3Err)
+	}
+}
+
+// runSample ... : sample 을 분석하여 c.id 가 보고된 라인 확인 (z3 로 검사하는 보안약점은 z3 가 없으면 생략)
+func runSample(t *testing.T, sa *sampleAnalyzer, c sampleCase, z3Err error) {
+	t.Run(c.file+"/"+c.id, func(t *testing.T) {
+		if c.solver && z3Err != nil {
+			t.Skipf("z3 is not available: %v", z3Err)
+		}
+
+		checkLines(t, sa.analyze(t, c.file), c)
+	})
+}
+
+// TestSolverSamples ... : z3 대신 항상 sat 을 출력하는 solver 로 z3 를 사용하는 보안약점이 보고되는 라인 확인
+// (sat 은 보안약점이 발생할 수 있다는 결과이므로 z3 로 검사하는 sample 은 보고되어야 하는 sample 만 확인)
+func TestSolverSamples(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake solver is a shell script")
+	}
+	dir, err := ioutil.TempDir("", "wah-z3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "z3"), []byte("#!/bin/sh\ncat >/dev/null\necho sat\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	defer os.Setenv("PATH", path)
+
+	sa := new(sampleAnalyzer)
+	sa.Init()
+	for _, c := range sampleCases {
+		if c.solver && len(c.lines) == 0 {
+			continue
+		}
+		c := c
+		t.Run(c.file+"/"+c.id, func(t *testing.T) {
+			checkLines(t, sa.analyze(t, c.file), c)
+		})
+	}
+}
+
+// checkLines ... : report 에서 c.id 가 보고된 라인이 c.lines 와 같은지 확인
+func checkLines(t *testing.T, report Report, c sampleCase) {
+	var lines []int
+	for _, f := range report.Findings {
+		if f.ID == c.id {
+			lines = append(lines, f.Line)
+		}
+	}
+	sort.Ints(lines)
+	if !reflect.DeepEqual(lines, c.lines) {
+		t.Errorf("%s reported at lines %v, want %v", c.id, lines, c.lines)
+	}
+}
+
+// existingSamples ... : 보안약점 항목을 추가하기 전부터 있던 testSrc 의 sample
+// SIL, CFG 생성을 바꿀 때 기존 분석 결과가 바뀌지 않는지 testdata/existing_samples.golden 과 비교
+var existingSamples = []string{
+	"BasicOffsetTbleTest.go", "FieldDecl.go", "GlobalDecl.go", "MapIter.go", "UncheckedInputArg.go", "emptyError.go",
+	"errhandle.go", "ex.go", "fortest.go", "phantomread.go", "rand.go", "rand2.go", "read_your_write.go",
+	"read_your_write_2.go", "sacc.go", "simple.go", "slicetest.go", "stringtest.go", "structtest.go", "syscom.go",
+	"systime.go", "test.go", "unarytest.go", "used_go.go", "varDecl.go",
+}
+
+// solverCCWs ... : z3 결과에 따라 달라지므로 golden 에서 제외하는 보안약점
+var solverCCWs = map[CCW]bool{READ_YOUR_WRITE: true, DUPLICATE_KEY_WRITE: true, UNCHECKED_ARITHMETIC: true, READ_AFTER_DELETE: true}
+
+// summarize ... : sample 의 분석 결과를 한 줄에 하나씩 기록 (보안약점 ID, 라인, 함수와 z3 와 관계없는 분석 오류)
+func summarize(file string, report Report, err error) []string {
+	if err != nil {
+		return []string{fmt.Sprintf("%s: error: %v", file, err)}
+	}
+	var lines []string
+	for _, f := range report.Findings {
+		if !solverCCWs[f.CCW] {
+			lines = append(lines, fmt.Sprintf("%s:%d: %s %s", file, f.Line, f.ID, f.Function))
+		}
+	}
+	for _, e := range report.Errors {
+		if !strings.Contains(e.Message, "could not run z3") {
+			lines = append(lines, fmt.Sprintf("%s: analysis error: %s: %s", file, e.Function, e.Message))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestExistingSamples(t *testing.T) {
+	sa := new(sampleAnalyzer)
+	sa.Init()
+
+	var lines []string
+	for _, file := range existingSamples {
+		report, err := sa.load(file)
+		lines = append(lines, summarize(file, report, err)...)
+	}
+	compareGolden(t, "existing_samples.golden", lines)
+}
+
+// duChains ... : sample 의 DU chain 을 한 줄에 하나씩 기록 (함수, offset, definition 과 use 의 SIL 라인)
+func duChains(file string, f *ast.File, fs *token.FileSet, info *types.Info) []string {
+	strPool, _, _, _, duChainofFunctions, err := GenerateGraphs(fs, []*ast.File{f}, info)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: error: %v", file, err)}
+	}
+	var lines []string
+	for k, chain := range duChainofFunctions {
+		chain := chain
+		funcName := strPool.LookupSymbolName(k)
+		for _, offset := range chain.Offsets() {
+			for _, set := range chain.LookUpFull(offset) {
+				uses := append([]int(nil), set.Use...)
+				sort.Ints(uses)
+				lines = append(lines, fmt.Sprintf("%s: %s: offset %s: def %d use %v", file, funcName, offset, set.Definition, uses))
+			}
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// SIL, DU chain 생성을 바꿀 때 기존 sample 의 def-use 관계가 바뀌지 않는지 testdata/existing_du_chains.golden 과 비교
+func TestExistingDUChains(t *testing.T) {
+	sa := new(sampleAnalyzer)
+	sa.Init()
+
+	var lines []string
+	for _, file := range existingSamples {
+		f, info, err := sa.check(file)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("%s: error: %v", file, err))
+			continue
+		}
+		lines = append(lines, duChains(file, f, sa.fs, info)...)
+	}
+	compareGolden(t, "existing_du_chains.golden", lines)
+}
+
+// compareGolden ... : lines 를 testdata 의 golden 파일과 비교 (-update 이면 golden 파일을 다시 씀)
+func compareGolden(t *testing.T, name string, lines []string) {
+	got := strings.Join(lines, "\n") + "\n"
+
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s changed (run go test -run %s -update to accept):\n%s", name, t.Name(), lineDiff(string(want), got))
+	}
+}
+
+// lineDiff ... : golden 에만 있는 라인 (-) 과 결과에만 있는 라인 (+)
+func lineDiff(want string, got string) string {
+	count := make(map[string]int)
+	for _, l := range strings.Split(want, "\n") {
+		count[l]++
+	}
+	for _, l := range strings.Split(got, "\n") {
+		count[l]--
+	}
+	var res []string
+	for l, n := range count {
+		for ; n > 0; n-- {
+			res = append(res, "- "+l)
+		}
+		for ; n < 0; n++ {
+			res = append(res, "+ "+l)
+		}
+	}
+	sort.Strings(res)
+	return strings.Join(res, "\n")
+}
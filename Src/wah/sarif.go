@@ -0,0 +1,266 @@
+package wah
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html) 출력에 필요한 최소한의 구조
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "WAH"
+	toolURI      = "https://github.com/sprituz/WAH_prototype_go"
+
+	// baseline fingerprint 의 partialFingerprints key
+	fingerprintKey = "wahFingerprint/v1"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	Severity string `json:"severity"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	RelatedLocations    []sarifLocation    `json:"relatedLocations,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	BaselineState       string             `json:"baselineState,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                    `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// sarifLevel ... : 심각도를 SARIF level (error, warning, note) 로 변환
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case High:
+		return "error"
+	case Medium:
+		return "warning"
+	}
+	return "note"
+}
+
+func sarifArtifact(file string) sarifArtifactLocation {
+	uri := filepath.ToSlash(file)
+	if filepath.IsAbs(file) {
+		if !strings.HasPrefix(uri, "/") {
+			uri = "/" + uri
+		}
+		return sarifArtifactLocation{URI: "file://" + uri}
+	}
+	return sarifArtifactLocation{URI: uri, URIBaseID: "%SRCROOT%"}
+}
+
+func sarifPhysical(file string, line int, column int) sarifPhysicalLocation {
+	location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact(file)}
+	if line > 0 {
+		location.Region = &sarifRegion{StartLine: line, StartColumn: column}
+	}
+	return location
+}
+
+func sarifResultOf(f Finding, ruleIndex map[CCW]int) sarifResult {
+	message := f.Message
+	if message == "" {
+		message = f.CCW.Description()
+	}
+
+	location := sarifLocation{PhysicalLocation: sarifPhysical(f.File, f.Line, f.Column)}
+	if f.Function != "" {
+		location.LogicalLocations = []sarifLogicalLocation{{Name: f.Function, Kind: "function"}}
+	}
+
+	result := sarifResult{
+		RuleID:    f.ID,
+		RuleIndex: ruleIndex[f.CCW],
+		Level:     sarifLevel(f.Severity),
+		Message:   sarifMessage{Text: message},
+		Locations: []sarifLocation{location},
+	}
+	if f.Fingerprint != "" {
+		result.PartialFingerprints = map[string]string{fingerprintKey: f.Fingerprint}
+	}
+	for i, related := range f.Related {
+		relatedLocation := sarifLocation{
+			ID:               i + 1,
+			PhysicalLocation: sarifPhysical(related.File, related.Line, related.Column),
+		}
+		if related.Message != "" {
+			relatedLocation.Message = &sarifMessage{Text: related.Message}
+		}
+		result.RelatedLocations = append(result.RelatedLocations, relatedLocation)
+	}
+	return result
+}
+
+// WriteSARIF ... : 탐지된 보안약점을 SARIF 2.1.0 형식으로 출력
+// CCW 마다 하나의 rule, 탐지 결과마다 하나의 result 를 생성
+// //wah:ignore 주석으로 억제된 보안약점은 suppressions 를 포함한 result 로, 오래된 주석은 notification 으로 출력
+func WriteSARIF(w io.Writer, report Report) error {
+	var rules []sarifRule
+	ruleIndex := make(map[CCW]int)
+	for i, ccw := range CCWList() {
+		ruleIndex[ccw] = i
+		rules = append(rules, sarifRule{
+			ID:                   ccw.ID(),
+			Name:                 ccw.String(),
+			ShortDescription:     sarifMessage{Text: ccw.String()},
+			Help:                 sarifMessage{Text: ccw.Description()},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(ccw.DefaultSeverity())},
+			Properties:           sarifProperties{Severity: ccw.DefaultSeverity().String()},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, f := range report.Findings {
+		result := sarifResultOf(f, ruleIndex)
+		if report.hasBaseline {
+			result.BaselineState = "new"
+		}
+		results = append(results, result)
+	}
+	for _, f := range report.Suppressed {
+		result := sarifResultOf(f, ruleIndex)
+		result.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: f.Justification}}
+		results = append(results, result)
+	}
+
+	// 더 이상 탐지되지 않는 baseline 항목은 absent 상태의 result 로 출력
+	for _, entry := range report.Fixed {
+		ccw, ok := lookupCCW(entry.ID)
+		if !ok {
+			continue
+		}
+
+		result := sarifResult{
+			RuleID:              entry.ID,
+			RuleIndex:           ruleIndex[ccw],
+			Level:               "none",
+			Message:             sarifMessage{Text: "fixed since the baseline was recorded"},
+			Locations:           []sarifLocation{{PhysicalLocation: sarifPhysical(entry.File, entry.Line, 0)}},
+			PartialFingerprints: map[string]string{fingerprintKey: entry.Fingerprint},
+			BaselineState:       "absent",
+		}
+		if entry.Function != "" {
+			result.Locations[0].LogicalLocations = []sarifLogicalLocation{{Name: entry.Function, Kind: "function"}}
+		}
+		results = append(results, result)
+	}
+
+	invocation := sarifInvocation{ExecutionSuccessful: len(report.Errors) == 0}
+	for _, e := range report.Errors {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+			Level:     "error",
+			Message:   sarifMessage{Text: e.Error()},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysical(e.File, 0, 0)}},
+		})
+	}
+	for _, stale := range report.Stale {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+			Level:     "warning",
+			Message:   sarifMessage{Text: suppressDirective + " " + strings.Join(stale.IDs, ",") + " does not match any weakness"},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysical(stale.File, stale.Line, 0)}},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool:        sarifTool{Driver: sarifDriver{Name: toolName, InformationURI: toolURI, Rules: rules}},
+			Invocations: []sarifInvocation{invocation},
+			Results:     results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
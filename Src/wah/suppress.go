@@ -0,0 +1,194 @@
+package wah
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// suppressDirective ... : 보안약점 탐지 결과를 무시하는 주석 (ex : //wah:ignore CCW-007,CCW-009 audited by security team)
+const suppressDirective = "//wah:ignore"
+
+// Suppression ...
+// //wah:ignore 주석 하나에 대한 정보
+// 함수 선언의 doc 주석 또는 함수 선언과 같은 라인에 있으면 함수 전체, 그 외에는 주석이 있는 라인
+// (코드 없이 주석만 있는 라인이면 다음 라인까지) 에 적용
+type Suppression struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Function string   `json:"function,omitempty"`
+	IDs      []string `json:"ids"`
+	Reason   string   `json:"reason,omitempty"`
+
+	startLine int
+	endLine   int
+	matched   map[string]bool
+}
+
+// parseSuppression ... : 주석 내용에서 CCW 식별자 목록과 사유를 분리
+// 식별자는 CCW-007 또는 PHANTOM_READS 형식이며 쉼표로 여러 개를 지정할 수 있음
+func parseSuppression(text string) ([]string, string, bool) {
+	if !strings.HasPrefix(text, suppressDirective) {
+		return nil, "", false
+	}
+	rest := strings.TrimPrefix(text, suppressDirective)
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil, "", false
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, "", true
+	}
+
+	var ids []string
+	for _, id := range strings.Split(fields[0], ",") {
+		if id == "" {
+			continue
+		}
+		ids = append(ids, normalizeCCWID(id))
+	}
+	return ids, strings.Join(fields[1:], " "), true
+}
+
+// normalizeCCWID ... : CCW 이름 또는 소문자로 작성된 식별자를 CCW-00N 형식으로 변환 (알 수 없는 식별자는 그대로 반환)
+func normalizeCCWID(id string) string {
+	for _, ccw := range CCWList() {
+		if strings.EqualFold(id, ccw.ID()) || strings.EqualFold(id, ccw.String()) {
+			return ccw.ID()
+		}
+	}
+	return id
+}
+
+// CollectSuppressions ... : 패키지의 모든 파일에서 //wah:ignore 주석을 수집
+func CollectSuppressions(fs *token.FileSet, files []*ast.File) []*Suppression {
+	var res []*Suppression
+
+	for _, f := range files {
+		// 라인마다 코드가 처음 시작하는 컬럼 (주석만 있는 라인인지 확인하기 위해 사용)
+		codeColumn := make(map[int]int)
+		ast.Inspect(f, func(n ast.Node) bool {
+			if n == nil {
+				return false
+			}
+			switch n.(type) {
+			case *ast.File:
+				return true
+			case *ast.CommentGroup, *ast.Comment:
+				return false
+			}
+			for _, pos := range []token.Pos{n.Pos(), n.End() - 1} {
+				position := fs.Position(pos)
+				if column, ok := codeColumn[position.Line]; !ok || position.Column < column {
+					codeColumn[position.Line] = position.Column
+				}
+			}
+			return true
+		})
+
+		for _, group := range f.Comments {
+			for _, comment := range group.List {
+				ids, reason, ok := parseSuppression(comment.Text)
+				if !ok {
+					continue
+				}
+
+				position := fs.Position(comment.Pos())
+				suppression := &Suppression{
+					File:      position.Filename,
+					Line:      position.Line,
+					IDs:       ids,
+					Reason:    reason,
+					startLine: position.Line,
+					endLine:   position.Line,
+					matched:   make(map[string]bool),
+				}
+
+				if funcDecl := suppressedFunc(fs, f, group, position.Line); funcDecl != nil {
+					suppression.Function = funcDecl.Name.Name
+					suppression.startLine = fs.Position(funcDecl.Pos()).Line
+					suppression.endLine = fs.Position(funcDecl.End()).Line
+				} else if column, ok := codeColumn[position.Line]; !ok || column > position.Column {
+					suppression.endLine = position.Line + 1
+				}
+
+				res = append(res, suppression)
+			}
+		}
+	}
+
+	return res
+}
+
+// suppressedFunc ... : 주석이 함수 선언의 doc 주석이거나 함수 선언과 같은 라인에 있으면 해당 함수를 반환
+func suppressedFunc(fs *token.FileSet, f *ast.File, group *ast.CommentGroup, line int) *ast.FuncDecl {
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if funcDecl.Doc == group || fs.Position(funcDecl.Pos()).Line == line {
+			return funcDecl
+		}
+	}
+	return nil
+}
+
+func (s *Suppression) match(f Finding) bool {
+	if f.File != s.File || f.Line < s.startLine || f.Line > s.endLine {
+		return false
+	}
+	for _, id := range s.IDs {
+		if id == f.ID {
+			s.matched[id] = true
+			return true
+		}
+	}
+	return false
+}
+
+// stale ... : 어떤 탐지 결과와도 일치하지 않은 식별자만 남긴 Suppression (모두 일치한 경우 nil)
+func (s *Suppression) stale() *Suppression {
+	var ids []string
+	for _, id := range s.IDs {
+		if !s.matched[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 && len(s.IDs) != 0 {
+		return nil
+	}
+
+	res := *s
+	res.IDs = ids
+	return &res
+}
+
+// ApplySuppressions ... : //wah:ignore 주석에 해당하는 탐지 결과를 분리
+// 억제된 탐지 결과와 어떤 탐지 결과와도 일치하지 않는 (오래된) 주석을 함께 반환
+func ApplySuppressions(findings []Finding, suppressions []*Suppression) (kept []Finding, suppressed []Finding, stale []Suppression) {
+	for _, f := range findings {
+		// 하나의 탐지 결과에 여러 주석이 적용될 수 있으므로 모든 주석을 검사
+		isSuppressed := false
+		for _, s := range suppressions {
+			if s.match(f) && !isSuppressed {
+				f.Justification = s.Reason
+				isSuppressed = true
+			}
+		}
+
+		if isSuppressed {
+			suppressed = append(suppressed, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+
+	for _, s := range suppressions {
+		if res := s.stale(); res != nil {
+			stale = append(stale, *res)
+		}
+	}
+	return kept, suppressed, stale
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+type ConfigContract struct {
+	contractapi.Contract
+}
+
+func (s *ConfigContract) SetOwner(ctx contractapi.TransactionContextInterface, owner string) error {
+	if err := ctx.GetStub().PutState("owner", []byte("")); err != nil {
+		return err
+	}
+	// 같은 key 에 다시 쓰므로 처음의 PutState 는 기록되지 않음
+	return ctx.GetStub().PutState("owner", []byte(owner))
+}
+
+func (s *ConfigContract) Rename(ctx contractapi.TransactionContextInterface, name string) ([]byte, error) {
+	if err := ctx.GetStub().PutState("name", []byte(name)); err != nil {
+		return nil, err
+	}
+	// 같은 transaction 에서 쓴 값은 GetState 로 읽히지 않음
+	return ctx.GetStub().GetState("name")
+}
+
+func main() {
+	cc, err := contractapi.NewChaincode(&ConfigContract{})
+	if err != nil {
+		panic(err)
+	}
+	cc.Start()
+}
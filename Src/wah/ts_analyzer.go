@@ -0,0 +1,303 @@
+package wah
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+)
+
+// timestampAdvice ... : system time 대신 사용할 함수
+const timestampAdvice = "use stub.GetTxTimestamp, which is the same on every endorsing peer"
+
+// timeSource ... : system time 을 읽는 호출 (ex : time.Now) 의 이름과 source 라인
+type timeSource struct {
+	name string
+	line int
+}
+
+// TSAnalyzer ...
+// system time (config.TimestampFuncs, 타입 정보로 찾은 time.Now, time.Since 등) 을 읽은 값이 DUChain 을 따라
+// ledger 쓰기 (PutState, DelState, PutPrivateData), event, 응답 (shim.Success, contract 의 트랜잭션 함수의 반환 값) 또는 분기 조건으로
+// 전달되는 코드 탐지 (SYSTEM_TIMESTAMP)
+// 로그 출력 등 endorsement 결과에 영향을 주지 않는 사용은 보고하지 않음
+// time.Unix 는 인자로 받은 값으로 시각을 만들 뿐 system time 을 읽지 않으므로 제외
+// (ex : GetTxTimestamp 의 결과를 변환하는 time.Unix(ts.Seconds, 0) 은 모든 peer 에서 같은 값)
+type TSAnalyzer struct {
+	analysisFile  string
+	fs            *token.FileSet
+	funcName      string
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	calls         map[int]map[string]*timeSource // system time 을 읽는 호출의 source 라인, 호출 이름 (system time 값의 method 호출 포함)
+	results       map[int]icg.CodeInfo           // 함수 호출의 반환 값을 저장하는 definition 라인, call
+	isTransaction bool                           // contract 의 트랜잭션 함수 (반환 값이 응답)
+	reported      map[int]bool                   // 보고한 sink 의 source 라인 (분기, 반환은 여러 경로에서 방문됨)
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+func (analyzer *TSAnalyzer) Init(analysisFile string, fs *token.FileSet, funcName string, chain vfg.DUChain, codeList []icg.CodeInfo) {
+	analyzer.analysisFile = analysisFile
+	analyzer.fs = fs
+	analyzer.funcName = funcName
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.calls = nil
+	analyzer.results = nil
+	analyzer.isTransaction = false
+	analyzer.reported = make(map[int]bool)
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+}
+
+// findCalls ... : 파일에서 system time 을 읽는 호출 (import 이름과 관계없이 타입 정보로 찾음) 과 분석 중인 함수가 contract 의 트랜잭션 함수인지 여부
+// SIL 은 method 호출의 receiver 를 push 하지 않으므로 (ex : now.Unix, time.Now().String) system time 을 저장한 변수, system time 값의 method 호출도 system time 을 읽는 호출로 기록
+func (analyzer *TSAnalyzer) findCalls(f *ast.File, info *types.Info) {
+	analyzer.calls = make(map[int]map[string]*timeSource)
+	vars := make(map[types.Object]*timeSource)
+
+	// expr 에서 사용된 system time (system time 을 읽는 호출, system time 을 저장한 변수)
+	sourceOf := func(expr ast.Expr) *timeSource {
+		var source *timeSource
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if source != nil {
+				return false
+			}
+			switch x := node.(type) {
+			case *ast.CallExpr:
+				if matchCall(calledFunc(x, info), analyzer.config.TimestampFuncs) {
+					source = &timeSource{icg.NodeString(analyzer.fs, x.Fun), analyzer.fs.Position(x.Pos()).Line}
+				}
+			case *ast.Ident:
+				source = vars[info.Uses[x]]
+			}
+			return true
+		})
+		return source
+	}
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) bool {
+		changed := false
+		for i, l := range lhs {
+			ident, ok := l.(*ast.Ident)
+			if !ok || len(rhs) == 0 {
+				continue
+			}
+			obj := info.Defs[ident]
+			if obj == nil {
+				obj = info.Uses[ident]
+			}
+			value := rhs[0]
+			if len(rhs) == len(lhs) {
+				value = rhs[i]
+			}
+			if source := sourceOf(value); obj != nil && vars[obj] == nil && source != nil {
+				vars[obj] = source
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	// system time 을 저장한 변수를 다른 변수에 대입하는 경우까지 반복
+	for changed := true; changed; {
+		changed = false
+		ast.Inspect(f, func(node ast.Node) bool {
+			switch x := node.(type) {
+			case *ast.AssignStmt:
+				changed = assign(x.Lhs, x.Rhs) || changed
+			case *ast.ValueSpec:
+				var lhs []ast.Expr
+				for _, name := range x.Names {
+					lhs = append(lhs, name)
+				}
+				changed = assign(lhs, x.Values) || changed
+			}
+			return true
+		})
+	}
+
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncDecl:
+			if obj, ok := info.Defs[x.Name].(*types.Func); ok && x.Name.Name == analyzer.funcName && isContractTransaction(obj) {
+				analyzer.isTransaction = true
+			}
+		case *ast.CallExpr:
+			source := sourceOf(x.Fun)
+			if source == nil && matchCall(calledFunc(x, info), analyzer.config.TimestampFuncs) {
+				source = &timeSource{icg.NodeString(analyzer.fs, x.Fun), analyzer.fs.Position(x.Pos()).Line}
+			}
+			if source == nil {
+				break
+			}
+			line := analyzer.fs.Position(x.Pos()).Line
+			if analyzer.calls[line] == nil {
+				analyzer.calls[line] = make(map[string]*timeSource)
+			}
+			analyzer.calls[line][icg.NodeString(analyzer.fs, x.Fun)] = source
+		}
+		return true
+	})
+}
+
+// readsTime ... : codes 에 system time 을 읽는 호출이 있으면 그 호출의 system time
+func (analyzer *TSAnalyzer) readsTime(codes []icg.CodeInfo) *timeSource {
+	for _, code := range codes {
+		call, ok := code.(*icg.ControlOpcode)
+		if !ok || call.Opcode() != icg.Call {
+			continue
+		}
+		if source := analyzer.calls[call.GetSourceLine()][fmt.Sprint(call.Params().Front().Value)]; source != nil {
+			return source
+		}
+	}
+	return nil
+}
+
+// findResults ... : 함수 호출의 (첫 번째) 반환 값을 저장하는 definition 라인과 그 call
+func (analyzer *TSAnalyzer) findResults() map[int]icg.CodeInfo {
+	results := make(map[int]icg.CodeInfo)
+	for _, code := range analyzer.codeList {
+		if code.Opcode() != icg.Call {
+			continue
+		}
+		if res := CallResults(code, analyzer.codeList); len(res) > 0 {
+			results[res[0].GetLine()] = code
+		}
+	}
+	return results
+}
+
+// trace ... : codes 의 값이 system time 에서 계산된 경우 system time 과 definition 경로
+// 함수 호출의 반환 값은 system time 을 읽는 호출이거나 인자가 system time 에서 계산된 경우 (ex : strconv.FormatInt(now.Unix(), 10)) system time 에서 계산된 값으로 취급
+func (analyzer *TSAnalyzer) trace(codes []icg.CodeInfo, visited map[int]bool) (*timeSource, []icg.CodeInfo, bool) {
+	if source := analyzer.readsTime(codes); source != nil {
+		return source, nil, true
+	}
+	if analyzer.results == nil {
+		analyzer.results = analyzer.findResults()
+	}
+
+	var source *timeSource
+	var argPath []icg.CodeInfo
+	path, ok := TraceDefinition(codes, analyzer.chain, analyzer.codeList, func(def int) bool {
+		call := analyzer.results[def]
+		if call == nil || visited[def] {
+			return false
+		}
+		visited[def] = true
+		if source = analyzer.readsTime([]icg.CodeInfo{call}); source != nil {
+			return true
+		}
+		for _, arg := range CallArguments(call, analyzer.codeList) {
+			var ok bool
+			if source, argPath, ok = analyzer.trace(arg, visited); ok {
+				return true
+			}
+		}
+		return false
+	})
+	return source, append(path, argPath...), ok
+}
+
+// stmtCodes ... : index 의 코드 (분기, 반환) 앞에서 같은 문장 (if, for, return) 에 속한 코드 (분기 조건, 반환 값을 계산하는 코드)
+func (analyzer *TSAnalyzer) stmtCodes(index int) []icg.CodeInfo {
+	stmt := analyzer.codeList[index].ParentStmt()
+	start := index
+	for ; start > 0; start-- {
+		code := analyzer.codeList[start-1]
+		if code.ParentStmt() != stmt {
+			break
+		}
+		switch code.Opcode() {
+		case icg.Label, icg.Fjp, icg.Tjp, icg.Ujp, icg.Proc, icg.Ret, icg.Retv:
+			return analyzer.codeList[start:index]
+		}
+	}
+	return analyzer.codeList[start:index]
+}
+
+func (analyzer *TSAnalyzer) report(line int, source *timeSource, path []icg.CodeInfo, use string) {
+	if analyzer.reported[line] {
+		return
+	}
+	analyzer.reported[line] = true
+
+	var ccw CCW = SYSTEM_TIMESTAMP
+	message := fmt.Sprintf("system time read by %s %s; %s", source.name, use, timestampAdvice)
+	finding := newFinding(ccw, analyzer.analysisFile, line, 0, message)
+	finding.AddRelated(analyzer.analysisFile, source.line, "system time is read by "+source.name)
+	// path 는 sink 에서 system time 방향이므로 역순으로 기록
+	for i := len(path) - 1; i >= 0; i-- {
+		offset := fmt.Sprint(path[i].(*icg.StackOpcode).Params().Front().Next().Value)
+		finding.AddRelated(analyzer.analysisFile, path[i].GetSourceLine(), fmt.Sprintf("system time is assigned (offset %s)", offset))
+	}
+
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// TSAnalysis ... : ledger 쓰기, event, 응답으로 전달되는 인자, 분기 조건, contract 의 트랜잭션 함수의 반환 값이 system time 에서 계산되었는지 검사
+func (analyzer *TSAnalyzer) TSAnalysis(f *ast.File, info *types.Info, block cfg.CFGBlock) int {
+	if f == nil {
+		return analyzer.analysisCount
+	}
+	if analyzer.calls == nil {
+		analyzer.findCalls(f, info)
+	}
+	if len(analyzer.calls) == 0 {
+		return analyzer.analysisCount
+	}
+
+	switch b := block.(type) {
+	case *cfg.CallBlock:
+		callOp, ok := b.CodeList()[0].(*icg.ControlOpcode)
+		if !ok || callOp.Opcode() != icg.Call {
+			break
+		}
+		funcName := fmt.Sprint(callOp.Params().Front().Value)
+		var use string
+		switch {
+		case matchFunc(funcName, analyzer.config.PutState) || matchFunc(funcName, analyzer.config.DelState) || matchFunc(funcName, analyzer.config.PrivateDataWrites):
+			use = "is written to the ledger by " + funcName
+		case matchFunc(funcName, analyzer.config.PublicSinks):
+			use = "is passed to " + funcName
+		default:
+			return analyzer.analysisCount
+		}
+		for _, arg := range CallArguments(callOp, analyzer.codeList) {
+			if source, path, ok := analyzer.trace(arg, make(map[int]bool)); ok {
+				analyzer.report(callOp.GetSourceLine(), source, path, use)
+				break
+			}
+		}
+	case *cfg.BranchBlock:
+		if b.BranchType() == cfg.UnconditionBranch {
+			break
+		}
+		jump := b.CodeList()[0]
+		if source, path, ok := analyzer.trace(analyzer.stmtCodes(findSILIndex(analyzer.codeList, jump.GetLine())), make(map[int]bool)); ok {
+			analyzer.report(jump.GetSourceLine(), source, path, "decides a branch condition")
+		}
+	case *cfg.ReturnBlock:
+		ret := b.CodeList()[0]
+		if !analyzer.isTransaction || ret.Opcode() != icg.Retv {
+			break
+		}
+		codes := analyzer.stmtCodes(findSILIndex(analyzer.codeList, ret.GetLine()))
+		if len(codes) == 0 {
+			break
+		}
+		// retv 에는 source 라인이 없으므로 반환 값의 라인에 보고
+		if source, path, ok := analyzer.trace(codes, make(map[int]bool)); ok {
+			analyzer.report(codes[len(codes)-1].GetSourceLine(), source, path, "is returned by transaction "+analyzer.funcName)
+		}
+	}
+	return analyzer.analysisCount
+}
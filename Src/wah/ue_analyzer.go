@@ -2,7 +2,6 @@ package wah
 
 import (
 	"fmt"
-	"strings"
 
 	"WAH_prototype_go-master/Src/analysisGraph/cfg"
 	"WAH_prototype_go-master/Src/analysisGraph/vfg"
@@ -17,6 +16,8 @@ type UEAnalyzer struct {
 	analysisCount int
 	detectedLint  []int
 	handledPoint map[string]int
+	findings      []Finding
+	config        *Config
 }
 
 func (analyzer *UEAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo) {
@@ -25,6 +26,7 @@ func (analyzer *UEAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeLis
 	analyzer.codeList = codeList
 	analyzer.analysisCount = 0
 	analyzer.handledPoint = make(map[string]int)
+	analyzer.config = DefaultConfig()
 
 }
 func (analyzer *UEAnalyzer) SetErrTable(errTable map[string]int) {
@@ -49,6 +51,9 @@ func (analyzer *UEAnalyzer) IsErrorVar(variable icg.CodeInfo, offset string) boo
 	}
 
 	defCodeIndex := findSILIndex(reverseCodeList, defLine)
+	if defCodeIndex == -1 { // 함수 안에 정의가 없는 경우 (매개변수, 전역 변수)
+		return res
+	}
 
 	analysisRange := reverseCodeList[defCodeIndex:]
 	sp := -analysisRange[0].GetPopParameterNum() //pop이 소모자, push가 판매
@@ -94,7 +99,7 @@ func (analyzer *UEAnalyzer) IsErrorVar(variable icg.CodeInfo, offset string) boo
 					if isDebug {
 						fmt.Printf("Inverse analysis completed!\n")
 						fmt.Printf("\t error code : %s\n \t target code : %s\n\n", analysisRange[0].String(), code.String())
-						fmt.Println("--------------------------------------------------------------------------------\n")
+						fmt.Print("--------------------------------------------------------------------------------\n\n")
 					}
 				}
 
@@ -126,18 +131,12 @@ func (analyzer *UEAnalyzer) isDetected(line int) bool {
 	}
 	return res
 }
-func (analyzer *UEAnalyzer) printAlarm(linenum int) {
+func (analyzer *UEAnalyzer) report(linenum int, message string) {
 	var ccw CCW = UNHANDLED_ERROR
-	if isFirstDetect {
-		fmt.Printf("chaincode weakness detected:\n")
-		isFirstDetect = false
-	}
-
 	if !analyzer.isDetected(linenum) {
 		analyzer.analysisCount++
 		analyzer.detectedLint = append(analyzer.detectedLint,linenum)
-		fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-		fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
+		analyzer.findings = append(analyzer.findings, newFinding(ccw, analyzer.analysisFile, linenum, 0, message))
 	}
 }
 func (analyzer *UEAnalyzer) IsEmptyHandling(block cfg.CFGBlock, endblock cfg.CFGBlock) bool{
@@ -149,7 +148,7 @@ func (analyzer *UEAnalyzer) IsEmptyHandling(block cfg.CFGBlock, endblock cfg.CFG
 		case *cfg.CallBlock:
 			opcode := b.CodeList()[0].(*icg.ControlOpcode)
 			funcName := fmt.Sprint(opcode.Params().Front().Value)
-			if strings.Contains(funcName, "shim.Error") || strings.Contains(funcName, "log.Fatal") {
+			if matchFunc(funcName, analyzer.config.ErrorHandlers) {
 				res = false
 				return res
 			} else {
@@ -191,7 +190,7 @@ func (analyzer *UEAnalyzer) UEAnalysis(block cfg.CFGBlock) int {
 					if useList, ok := analyzer.chain.LookUpUseOfDef(offsetStr, sil.GetLine()); ok {
 						// error 변수인데 사용된 곳이 없다면 핸들링 되지 않은 것
 						if len(useList) == 0 {
-							analyzer.printAlarm(sil.GetSourceLine())
+							analyzer.report(sil.GetSourceLine(), "the error variable is never checked")
 						} else {
 							// error handling 하는 if문에서 사용되었는지
 							for _, use := range useList {
@@ -203,7 +202,7 @@ func (analyzer *UEAnalyzer) UEAnalysis(block cfg.CFGBlock) int {
 								if useSIL.ParentStmt() != icg.IfStmt {
 									if p,ok := analyzer.handledPoint[offset]; ok {
 										if p > useSIL.GetLine() {
-											analyzer.printAlarm(useSIL.GetSourceLine())
+											analyzer.report(useSIL.GetSourceLine(), "the error variable is used before it is handled")
 										}
 									}
 
@@ -211,16 +210,16 @@ func (analyzer *UEAnalyzer) UEAnalysis(block cfg.CFGBlock) int {
 									if bBlock , ok := b.LinkedBlock().(*cfg.BranchBlock); ok {
 										// empty handling을 잡아내기 위해 if문 조건을 만족하는 블록만 검사
 										if bBlock.UjpBlock().BlockNumber() == bBlock.TargetBlock().BlockNumber() {
-											analyzer.printAlarm(useSIL.GetSourceLine())
+											analyzer.report(useSIL.GetSourceLine(), "the error is checked with an empty block")
 										}else if bBlock.BranchType() ==cfg.FalseBranch  &&  bBlock.UjpBlock() != nil{
 											if analyzer.IsEmptyHandling(bBlock.UjpBlock(),bBlock.TargetBlock()) {
-												analyzer.printAlarm(useSIL.GetSourceLine())
+												analyzer.report(useSIL.GetSourceLine(), "the error is checked but not handled")
 											}else {
 												analyzer.handledPoint[offset] = useSIL.GetLine()
 											}
 										}else if bBlock.BranchType() == cfg.TrueBranch && bBlock.TargetBlock() != nil {
 											if analyzer.IsEmptyHandling(bBlock.TargetBlock(),bBlock.TargetBlock()) {
-												analyzer.printAlarm(useSIL.GetSourceLine())
+												analyzer.report(useSIL.GetSourceLine(), "the error is checked but not handled")
 											}else {
 												analyzer.handledPoint[offset] = useSIL.GetLine()
 											}
@@ -238,11 +237,17 @@ func (analyzer *UEAnalyzer) UEAnalysis(block cfg.CFGBlock) int {
 				offsetStr := fmt.Sprint(arg.Params().Front().Next().Value)
 				addressOffset = append(addressOffset, offsetStr)
 			case icg.Ldi:
+				// pointer 가 가리키는 struct 의 field (lod, ldi.t, pop2) 는 lda 로 주소를 계산하지 않음
+				if len(addressOffset) == 0 {
+					break
+				}
 				offsetStr := addressOffset[len(addressOffset)-1]
 				analyzer.IsErrorVar(sil, offsetStr)
 
 			case icg.Sti:
-				addressOffset = addressOffset[0 : len(addressOffset)-1]
+				if len(addressOffset) > 0 {
+					addressOffset = addressOffset[0 : len(addressOffset)-1]
+				}
 			}
 
 		}
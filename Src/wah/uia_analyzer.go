@@ -15,7 +15,9 @@ type UIAAnalyzer struct {
 	checkPoint    map[string]int // offset , line number
 	analysisCount int
 	taintList     []TaintInfo
+	taintSource   *TaintInfo // 마지막으로 IsTaintedVar 가 찾은 오염원
 	detectedLint  []int
+	findings      []Finding
 }
 type TaintInfo struct {
 	offset   string
@@ -83,6 +85,9 @@ func (analyzer *UIAAnalyzer) IsUncheckedVar(variable icg.CodeInfo, offset string
 	}
 
 	defCodeIndex := findSILIndex(reverseCodeList, defLine)
+	if defCodeIndex == -1 { // 함수 안에 정의가 없는 경우 (매개변수, 전역 변수)
+		return res
+	}
 
 	analysisRange := reverseCodeList[defCodeIndex:]
 	sp := -analysisRange[0].GetPopParameterNum() //pop이 소모자, push가 판매
@@ -119,7 +124,7 @@ func (analyzer *UIAAnalyzer) IsUncheckedVar(variable icg.CodeInfo, offset string
 			if isDebug {
 				fmt.Printf("Unchecked variable analysis completed!\n")
 				fmt.Printf("\t definition code : %s\n \t target code : %s\n\n", analysisRange[0].String(), code.String())
-				fmt.Println("--------------------------------------------------------------------------------\n")
+				fmt.Print("--------------------------------------------------------------------------------\n\n")
 			}
 			break
 		}
@@ -143,21 +148,18 @@ func (analyzer *UIAAnalyzer) isDetected(line int) bool{
 	}
 	return res
 }
-func (analyzer *UIAAnalyzer) printAlarm(linenum int) {
+func (analyzer *UIAAnalyzer) report(linenum int, offset string) {
 	var ccw CCW = UNCHECKED_INPUT_ARGUMENTS
-	if isFirstDetect {
-		fmt.Printf("chaincode weakness detected:\n")
-		isFirstDetect = false
-	}
 	if !analyzer.isDetected(linenum) {
 		analyzer.detectedLint = append(analyzer.detectedLint,linenum)
-		fmt.Printf("\t CCW-%03d : %s\n", ccw, ccw.String())
-		fmt.Printf("\t %s : %d\n\n", analyzer.analysisFile, linenum)
+		message := fmt.Sprintf("the input argument (offset %s) is used without validation", offset)
+		finding := newFinding(ccw, analyzer.analysisFile, linenum, 0, message)
+		if analyzer.taintSource != nil {
+			finding.AddRelated(analyzer.analysisFile, analyzer.taintSource.codeInfo.GetSourceLine(), fmt.Sprintf("unchecked input source (stored to offset %s)", analyzer.taintSource.offset))
+		}
+		analyzer.findings = append(analyzer.findings, finding)
 		analyzer.analysisCount++
 	}
-
-
-
 }
 
 func findAddressOffset(codeList []icg.CodeInfo, index int) string {
@@ -181,6 +183,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 	reverseCodeList := sliceReverse(analyzer.codeList)
 	defLine := 0
 	res := false
+	analyzer.taintSource = nil
 	// 변수가 이미 오염된 변수인경우
 
 	if variable.Opcode() == icg.Lod {
@@ -191,6 +194,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 			def := taint.codeInfo.GetLine()
 			if defLine == def {
 				res = true
+				analyzer.taintSource = &taint
 				break
 			}
 		}
@@ -201,6 +205,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 			def := taint.codeInfo.GetLine()
 			if defLine == def {
 				res = true
+				analyzer.taintSource = &taint
 				break
 			}
 		}
@@ -213,6 +218,9 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 	if !res {
 
 		defCodeIndex := findSILIndex(reverseCodeList, defLine)
+		if defCodeIndex == -1 { // 함수 안에 정의가 없는 경우 (매개변수, 전역 변수)
+			return res
+		}
 
 		analysisRange := reverseCodeList[defCodeIndex:]
 		sp := -analysisRange[0].GetPopParameterNum() //pop이 소모자, push가 판매
@@ -244,6 +252,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 					taintedDef := taint.codeInfo.GetLine()
 					if rhsDef == taintedDef {
 						res = true
+						analyzer.taintSource = &taint
 						if isDebug {
 							fmt.Printf("The variable %s is tainted by %s\n\n", analysisRange[0].String(), code.String())
 						}
@@ -258,6 +267,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 					taintedDef := taint.codeInfo.GetLine()
 					if rhsDef == taintedDef {
 						res = true
+						analyzer.taintSource = &taint
 						if isDebug {
 							fmt.Printf("The variable %s is tainted by %s\n", analysisRange[0].String(), code.String())
 						}
@@ -270,7 +280,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 				if isDebug {
 					fmt.Printf("tainted analysis completed!\n")
 					fmt.Printf("\t definition code : %s\n \t is tainted : %v\n\n", analysisRange[0].String(), res)
-					fmt.Println("--------------------------------------------------------------------------------\n")
+					fmt.Print("--------------------------------------------------------------------------------\n\n")
 				}
 				break
 			}
@@ -282,7 +292,7 @@ func (analyzer *UIAAnalyzer) IsTaintedVar(variable icg.CodeInfo, offset string)
 			fmt.Println("--------------------------------------------------------------------------------")
 
 			fmt.Printf("The variable %s is already tainted \n", variable)
-			fmt.Println("--------------------------------------------------------------------------------\n")
+			fmt.Print("--------------------------------------------------------------------------------\n\n")
 
 		}
 	}
@@ -331,10 +341,10 @@ func (analyzer *UIAAnalyzer) UIAAnalysis(block cfg.CFGBlock) int {
 					} else {
 						if checkPoint, ok := analyzer.checkPoint[offsetStr]; ok {
 							if checkPoint > sil.GetLine() {
-								analyzer.printAlarm(sil.GetSourceLine())
+								analyzer.report(sil.GetSourceLine(), offsetStr)
 							}
 						} else {
-							analyzer.printAlarm(sil.GetSourceLine())
+							analyzer.report(sil.GetSourceLine(), offsetStr)
 						}
 					}
 				}
@@ -344,6 +354,10 @@ func (analyzer *UIAAnalyzer) UIAAnalysis(block cfg.CFGBlock) int {
 				offsetStr := fmt.Sprint(arg.Params().Front().Next().Value)
 				addressOffset = append(addressOffset, offsetStr)
 			case icg.Ldi:
+				// pointer 가 가리키는 struct 의 field (lod, ldi.t, pop2) 는 lda 로 주소를 계산하지 않음
+				if len(addressOffset) == 0 {
+					break
+				}
 				offsetStr := addressOffset[len(addressOffset)-1]
 
 				//현재 블록에 있는 def가 global인가
@@ -353,11 +367,11 @@ func (analyzer *UIAAnalyzer) UIAAnalysis(block cfg.CFGBlock) int {
 					} else {
 						if checkPoint, ok := analyzer.checkPoint[offsetStr]; ok {
 							if checkPoint > sil.GetLine() {
-								analyzer.printAlarm(sil.GetSourceLine())
+								analyzer.report(sil.GetSourceLine(), offsetStr)
 								//analyzer.analysisCount++
 							}
 						} else {
-							analyzer.printAlarm(sil.GetSourceLine())
+							analyzer.report(sil.GetSourceLine(), offsetStr)
 							//analyzer.analysisCount++
 						}
 					}
@@ -365,7 +379,9 @@ func (analyzer *UIAAnalyzer) UIAAnalysis(block cfg.CFGBlock) int {
 				}
 
 			case icg.Sti:
-				addressOffset = addressOffset[0 : len(addressOffset)-1]
+				if len(addressOffset) > 0 {
+					addressOffset = addressOffset[0 : len(addressOffset)-1]
+				}
 			}
 
 		}
@@ -0,0 +1,219 @@
+package wah
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"WAH_prototype_go-master/Src/analysisGraph/cfg"
+	"WAH_prototype_go-master/Src/analysisGraph/vfg"
+	"WAH_prototype_go-master/Src/icg"
+	"WAH_prototype_go-master/Src/icg/symbolTable"
+)
+
+// WWAnalyzer ...
+// 하나의 실행 경로에서 같은 key 에 두 번 쓰는 코드 탐지 (DUPLICATE_KEY_WRITE)
+// 두 PutState, DelState 의 key 가 같을 수 있는지는 RYWAnalyzer 의 SMT formula 생성 (GetFormulaGenRange, makeSMTFormula) 과 z3 로 판단
+type WWAnalyzer struct {
+	analysisFile  string
+	chain         vfg.DUChain
+	codeList      []icg.CodeInfo
+	formula       *RYWAnalyzer // SMT formula 생성기 (함수 안의 모든 key 가 같은 symbol 을 공유)
+	constraints   []string     // key 를 만드는 변수들의 정의 (formula)
+	writes        []keyWrite   // 현재 경로에서 호출된 PutState, DelState
+	solverErr     error        // z3 를 실행할 수 없는 경우 (이후의 key 비교는 생략)
+	analysisCount int
+	findings      []Finding
+	config        *Config
+}
+
+// keyWrite ... : ledger 쓰기 호출 하나 (term 은 key 의 SMT 표현식)
+// SMT symbol 은 definition 이 아닌 변수마다 만들어지므로 key 에 사용된 변수의 reaching definition 과 literal 값을 함께 기록
+type keyWrite struct {
+	funcName  string
+	line      int
+	term      string
+	defs      string
+	literal   string
+	isLiteral bool
+}
+
+func (analyzer *WWAnalyzer) Init(analysisFile string, chain vfg.DUChain, codeList []icg.CodeInfo, litTable *symbolTable.LiteralTable) {
+	analyzer.analysisFile = analysisFile
+	analyzer.chain = chain
+	analyzer.codeList = codeList
+	analyzer.analysisCount = 0
+	analyzer.config = DefaultConfig()
+
+	// formula 생성에만 사용하므로 z3 context 는 만들지 않음
+	analyzer.formula = new(RYWAnalyzer)
+	analyzer.formula.analysisFile = analysisFile
+	analyzer.formula.chain = chain
+	analyzer.formula.codeList = codeList
+	analyzer.formula.litTable = litTable
+	analyzer.formula.symbolList = make(map[string]*SMTSymbol)
+}
+
+// Copy ... : 분기 이전의 쓰기 목록으로 되돌리기 위한 복사 (formula 생성기는 공유)
+func (analyzer *WWAnalyzer) Copy() *WWAnalyzer {
+	newAnalyzer := new(WWAnalyzer)
+	*newAnalyzer = *analyzer
+	newAnalyzer.writes = append([]keyWrite(nil), analyzer.writes...)
+	newAnalyzer.constraints = append([]string(nil), analyzer.constraints...)
+
+	return newAnalyzer
+}
+
+func (analyzer *WWAnalyzer) report(prev keyWrite, cur keyWrite, formula string) {
+	var ccw CCW = DUPLICATE_KEY_WRITE
+	message := fmt.Sprintf("%s may write the same key as %s at line %d; the first write is lost", cur.funcName, prev.funcName, prev.line)
+	finding := newFinding(ccw, analyzer.analysisFile, cur.line, 0, message)
+	finding.AddRelated(analyzer.analysisFile, prev.line, prev.funcName)
+	finding.Evidence = formula
+	analyzer.findings = append(analyzer.findings, finding)
+	analyzer.analysisCount++
+}
+
+// modelKey ... : key 를 만드는 코드가 SMT formula 로 표현 가능한지 (문자열 literal, 변수, 문자열 연결만 사용) 검사
+// 함수 안에 정의가 없는 변수 (매개변수, 전역 변수) 는 자유 변수로 등록
+func (analyzer *WWAnalyzer) modelKey(codes []icg.CodeInfo, visited map[int]bool) bool {
+	for _, code := range codes {
+		switch code.Opcode() {
+		case icg.Lda:
+			if code.Type() != icg.Sp {
+				return false
+			}
+		case icg.Lod:
+			lod := code.(*icg.StackOpcode)
+			offset := fmt.Sprint(lod.Params().Front().Next().Value)
+			if strings.HasPrefix(offset, "$") { // 함수 호출 결과
+				return false
+			}
+
+			def, ok := analyzer.chain.LookUpDefOfUse(offset, lod.GetLine())
+			if ok && visited[def] {
+				continue
+			}
+			visited[def] = true
+
+			var genRange []icg.CodeInfo
+			if ok && def != -1 {
+				genRange = analyzer.formula.GetFormulaGenRange(lod, offset)
+			}
+			if len(genRange) == 0 {
+				if _, ok := analyzer.formula.symbolList[offset]; !ok {
+					analyzer.formula.symbolList[offset] = analyzer.formula.makeSymbol(*lod)
+				}
+				continue
+			}
+			if !analyzer.modelKey(genRange[:len(genRange)-1], visited) {
+				return false
+			}
+		case icg.Add, icg.Ldc:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// keyDefinitions ... : key 를 만드는 코드에서 사용된 변수마다 reaching definition 라인 (함수 안에 정의가 없으면 "-")
+func (analyzer *WWAnalyzer) keyDefinitions(codes []icg.CodeInfo) string {
+	var defs []string
+	for _, code := range codes {
+		if code.Opcode() != icg.Lod {
+			continue
+		}
+		def := "-"
+		if path, ok := TraceDefinition([]icg.CodeInfo{code}, analyzer.chain, analyzer.codeList, func(int) bool { return true }); ok {
+			def = fmt.Sprint(path[0].GetLine())
+		}
+		defs = append(defs, fmt.Sprintf("%v:%s", code.(*icg.StackOpcode).Params().Front().Next().Value, def))
+	}
+	return strings.Join(defs, ",")
+}
+
+// mayAlias ... : 두 key 가 같을 수 있는지 검사하고 사용한 SMT formula 를 반환
+func (analyzer *WWAnalyzer) mayAlias(prev keyWrite, cur keyWrite) (string, bool) {
+	if prev.isLiteral && cur.isLiteral {
+		return "", prev.literal == cur.literal
+	}
+	if prev.term == cur.term {
+		// 같은 변수라도 다른 definition 에서 만든 key (ex : key = "a"; PutState(key); key = "b"; PutState(key)) 는 다를 수 있음
+		// 같은 변수의 definition 들은 같은 SMT symbol 을 공유하므로 z3 로 비교하지 않음
+		return "", prev.defs == cur.defs
+	}
+	if strings.HasPrefix(prev.term, "\"") && strings.HasPrefix(cur.term, "\"") {
+		return "", false
+	}
+	if analyzer.solverErr != nil {
+		return "", false
+	}
+
+	assertion := fmt.Sprintf("(= %s %s)", prev.term, cur.term)
+	for i := len(analyzer.constraints) - 1; i >= 0; i-- {
+		assertion = fmt.Sprintf("(and %s %s)", analyzer.constraints[i], assertion)
+	}
+
+	var symbols []string
+	for _, sym := range analyzer.formula.symbolList {
+		symbols = append(symbols, sym.symbolName)
+	}
+	sort.Strings(symbols)
+
+	var formula strings.Builder
+	for _, sym := range symbols {
+		// key 로 사용되는 값은 모두 문자열
+		formula.WriteString(fmt.Sprintf("(declare-const %s String)\n", sym))
+	}
+	formula.WriteString(fmt.Sprintf("(assert %s)\n(check-sat)\n", assertion))
+
+	sat, err := checkSat(formula.String())
+	if err != nil {
+		analyzer.solverErr = err
+		return "", false
+	}
+	return formula.String(), sat
+}
+
+func (analyzer *WWAnalyzer) WWAnalysis(block cfg.CFGBlock) int {
+	switch b := block.(type) {
+	case *cfg.CallBlock:
+		opcode := b.CodeList()[0]
+		callOp, ok := opcode.(*icg.ControlOpcode)
+		if !ok || callOp.Opcode() != icg.Call {
+			break
+		}
+		funcName := fmt.Sprint(callOp.Params().Front().Value)
+		if !matchFunc(funcName, analyzer.config.PutState) && !matchFunc(funcName, analyzer.config.DelState) {
+			break
+		}
+
+		// key 를 SMT formula 로 표현할 수 없으면 (ex : 함수 호출 결과) 같은지 판단하지 않음
+		args := CallArguments(callOp, analyzer.codeList)
+		if len(args) == 0 || !analyzer.modelKey(args[0], make(map[int]bool)) {
+			break
+		}
+		analyzer.formula.opStack = nil
+		if formula := analyzer.formula.makeSMTFormula(args[0]); strings.HasPrefix(formula, "(") {
+			analyzer.constraints = append(analyzer.constraints, formula)
+		}
+		if len(analyzer.formula.opStack) == 0 {
+			break
+		}
+
+		cur := keyWrite{funcName: funcName, line: callOp.GetSourceLine(), term: analyzer.formula.opStack[len(analyzer.formula.opStack)-1], defs: analyzer.keyDefinitions(args[0])}
+		cur.literal, cur.isLiteral = literalValue(args[0], analyzer.chain, analyzer.codeList, analyzer.formula.litTable)
+		for _, prev := range analyzer.writes {
+			// 반복문으로 같은 호출을 다시 분석하는 경우는 제외
+			if prev.line == cur.line {
+				continue
+			}
+			if formula, ok := analyzer.mayAlias(prev, cur); ok {
+				analyzer.report(prev, cur, formula)
+			}
+		}
+		analyzer.writes = append(analyzer.writes, cur)
+	}
+	return analyzer.analysisCount
+}
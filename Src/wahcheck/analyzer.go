@@ -0,0 +1,150 @@
+// Package wahcheck ... : WAH 의 보안약점 분석을 golang.org/x/tools/go/analysis 의 Analyzer 로 제공
+// (go vet -vettool, gopls, multichecker 등 go/analysis driver 에서 사용)
+//
+// Analyzer 는 패키지마다 한 번 AST, SIL/CFG/DUChain 기반 분석을 모두 수행하고,
+// CCW 마다 하나씩 있는 Analyzer 가 그 결과 중 해당 CCW 의 보안약점을 pass.Report 로 보고함
+package wahcheck
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"WAH_prototype_go-master/Src/wah"
+)
+
+var (
+	configFile  string
+	allPackages bool
+)
+
+// Analyzer ... : 패키지 하나에 대해 WAH 의 모든 분석을 수행하고 결과 (*wah.Report) 를 CCW 별 Analyzer 에 전달
+// 분석 중 발생한 오류만 직접 보고함
+var Analyzer = &analysis.Analyzer{
+	Name:       "wah",
+	Doc:        "run the WAH chaincode weakness analysis (AST and SIL/CFG/DU chain based checks)",
+	Run:        run,
+	ResultType: reflect.TypeOf(new(wah.Report)),
+}
+
+// Analyzers ... : Analyzer 와 CCW 별 Analyzer 목록 (multichecker 에 그대로 전달)
+var Analyzers []*analysis.Analyzer
+
+func init() {
+	Analyzer.Flags.StringVar(&configFile, "config", "", "WAH project configuration file (.wah.yaml or .wah.json)")
+	Analyzer.Flags.BoolVar(&allPackages, "all", false, "analyze every package, not only the packages importing the chaincode shim or contract API")
+
+	Analyzers = append(Analyzers, Analyzer)
+	for _, ccw := range wah.CCWList() {
+		Analyzers = append(Analyzers, newCCWAnalyzer(ccw))
+	}
+}
+
+// chaincodeImports ... : 체인코드 패키지로 판단하는 import 경로
+var chaincodeImports = []string{
+	"github.com/hyperledger/fabric-chaincode-go/shim",
+	"github.com/hyperledger/fabric/core/chaincode/shim",
+	"github.com/hyperledger/fabric-contract-api-go/contractapi",
+}
+
+func isChaincode(pass *analysis.Pass) bool {
+	for _, imported := range pass.Pkg.Imports() {
+		for _, path := range chaincodeImports {
+			if imported.Path() == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func run(pass *analysis.Pass) (res interface{}, err error) {
+	report := new(wah.Report)
+	if !allPackages && !isChaincode(pass) {
+		return report, nil
+	}
+
+	config := wah.DefaultConfig()
+	if configFile != "" {
+		if config, err = wah.LoadConfig(configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// SIL, CFG, DUChain 생성 중 발생한 panic 은 AnalysisFiles 가 Report.Errors 로 기록하므로, 그 밖의 panic 만 패키지의 분석 오류로 처리
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = nil, fmt.Errorf("wah: %v", r)
+		}
+	}()
+	*report = wah.AnalysisFiles(pass.Fset, pass.Files, pass.TypesInfo, config)
+
+	for _, e := range report.Errors {
+		pass.Report(analysis.Diagnostic{
+			Pos:      position(pass, e.File, 1, 0),
+			Category: "analysis-error",
+			Message:  fmt.Sprintf("wah: analysis is incomplete: %v", e),
+		})
+	}
+	return report, nil
+}
+
+func newCCWAnalyzer(ccw wah.CCW) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:     strings.ToLower(ccw.String()),
+		Doc:      fmt.Sprintf("report %s %s\n\n%s", ccw.ID(), ccw.String(), ccw.Description()),
+		Requires: []*analysis.Analyzer{Analyzer},
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			report := pass.ResultOf[Analyzer].(*wah.Report)
+			for _, f := range report.Findings {
+				if f.CCW == ccw {
+					pass.Report(diagnostic(pass, f))
+				}
+			}
+			return nil, nil
+		},
+	}
+}
+
+func diagnostic(pass *analysis.Pass, f wah.Finding) analysis.Diagnostic {
+	message := f.Message
+	if message == "" {
+		message = f.CCW.Description()
+	}
+
+	d := analysis.Diagnostic{
+		Pos:      position(pass, f.File, f.Line, f.Column),
+		Category: f.ID,
+		Message:  fmt.Sprintf("%s %s (%s): %s", f.ID, f.Name, f.Severity, message),
+	}
+	for _, related := range f.Related {
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     position(pass, related.File, related.Line, related.Column),
+			Message: related.Message,
+		})
+	}
+	return d
+}
+
+// position ... : 파일 이름, 라인, 컬럼을 pass.Fset 의 위치로 변환 (찾을 수 없으면 패키지 선언 위치)
+func position(pass *analysis.Pass, file string, line int, column int) token.Pos {
+	for _, f := range pass.Files {
+		tf := pass.Fset.File(f.Pos())
+		if tf == nil || tf.Name() != file {
+			continue
+		}
+		if line < 1 || line > tf.LineCount() {
+			return f.Package
+		}
+
+		pos := tf.LineStart(line)
+		if column > 1 {
+			pos += token.Pos(column - 1)
+		}
+		return pos
+	}
+	return pass.Files[0].Package
+}